@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bufio"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/spf13/cobra"
+)
+
+var restoreYes bool
+
+// backupCmd writes a consistent snapshot of the database, safe to run while
+// other pulse commands are writing.
+var backupCmd = &cobra.Command{
+	Use:   "backup [path]",
+	Short: "Write a consistent snapshot of the database",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := databasePath()
+		if err != nil {
+			return err
+		}
+		dbh, err := db.Open(path)
+		if err != nil {
+			return err
+		}
+		defer dbh.Close()
+
+		dest := ""
+		if len(args) == 1 {
+			dest = args[0]
+		} else {
+			dir, err := cfg.BackupDir(path)
+			if err != nil {
+				return err
+			}
+			dest = filepath.Join(dir, "manual-"+time.Now().Format("20060102-150405")+".db")
+		}
+		if err := db.Backup(dbh, dest); err != nil {
+			return err
+		}
+		fmt.Println("Backed up to", dest)
+		return nil
+	},
+}
+
+// restoreCmd replaces the database with a validated backup, keeping a safety
+// copy of the current one.
+var restoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Replace the database with a backup",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		src := args[0]
+		version, err := db.ValidateBackup(src)
+		if err != nil {
+			return err
+		}
+		path, err := databasePath()
+		if err != nil {
+			return err
+		}
+
+		if !restoreYes {
+			fmt.Printf("Replace %s with %s (schema v%d)? [y/N] ", path, src, version)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("Aborted.")
+				return nil
+			}
+		}
+
+		// Keep a copy of what we are about to overwrite
+		if _, err := os.Stat(path); err == nil {
+			dir, err := cfg.BackupDir(path)
+			if err != nil {
+				return err
+			}
+			safety := filepath.Join(dir, "pre-restore-"+time.Now().Format("20060102-150405")+".db")
+			cur, err := db.Connect(path)
+			if err != nil {
+				return err
+			}
+			err = db.Backup(cur, safety)
+			cur.Close()
+			if err != nil {
+				return err
+			}
+			fmt.Println("Current database saved to", safety)
+		}
+
+		if err := db.ReplaceDatabase(src, path); err != nil {
+			return err
+		}
+		// Bring an older backup up to the current schema
+		dbh, err := db.Open(path)
+		if err != nil {
+			return err
+		}
+		dbh.Close()
+		fmt.Println("Restored", path, "from", src)
+		return nil
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolVarP(&restoreYes, "yes", "y", false, "Do not ask for confirmation")
+	rootCmd.AddCommand(backupCmd, restoreCmd)
+}
+
+// autoBackup takes the daily/weekly rotating snapshots when backup.auto is on.
+// Failures are reported but never stop the command that triggered them.
+func autoBackup(path string, dbh *sql.DB) {
+	dir, err := cfg.BackupDir(path)
+	if err == nil {
+		_, err = db.RotateBackups(dbh, dir, cfg.Backup.KeepDaily, cfg.Backup.KeepWeekly, time.Now().In(cfg.Location()))
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "pulse: automatic backup failed:", err)
+	}
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var migrateStatus bool
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Database maintenance",
+}
+
+// dbMigrateCmd applies pending schema migrations, or lists them with --status.
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply pending schema migrations",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := databasePath()
+		if err != nil {
+			return err
+		}
+		dbh, err := db.Connect(path)
+		if err != nil {
+			return err
+		}
+		defer dbh.Close()
+
+		if migrateStatus {
+			st, err := db.Status(dbh)
+			if err != nil {
+				return err
+			}
+			for _, s := range st {
+				name := fmt.Sprintf("%04d_%s", s.Version, s.Name)
+				if s.Applied {
+					fmt.Println(ui.DefaultTheme.Success.Render("  applied ") + ui.DefaultTheme.Value.Render(name) + ui.DefaultTheme.Hint.Render("  "+s.AppliedAt))
+				} else {
+					fmt.Println(ui.DefaultTheme.Error.Render("  pending ") + ui.DefaultTheme.Value.Render(name))
+				}
+			}
+			return nil
+		}
+
+		applied, err := db.Migrate(dbh)
+		for _, m := range applied {
+			fmt.Printf("Applied %04d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		if len(applied) == 0 {
+			fmt.Printf("Schema is up to date (version %d).\n", db.LatestVersion())
+		}
+		return nil
+	},
+}
+
+func init() {
+	dbMigrateCmd.Flags().BoolVar(&migrateStatus, "status", false, "Show applied and pending migrations without applying them")
+	dbCmd.AddCommand(dbMigrateCmd)
+	rootCmd.AddCommand(dbCmd)
+}
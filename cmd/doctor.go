@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/doctor"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	doctorFix      bool
+	doctorMaxTimer time.Duration
+)
+
+// doctorCmd checks the database for corruption and drift, repairing with --fix.
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the database for problems (repair with --fix)",
+	Long: `Runs these checks:
+	integrity   PRAGMA integrity_check
+	fts         search index consistency with entries (rebuilt by --fix)
+	timestamps  ts/started_at/ended_at values not in canonical UTC form
+	tags        entries.tags out of sync with the tag table
+	timers      timers running longer than --max-timer (stopped by --fix)
+
+With --fix a backup is written first.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path, err := databasePath()
+		if err != nil {
+			return err
+		}
+		dbh, err := db.Open(path)
+		if err != nil {
+			return err
+		}
+		defer dbh.Close()
+
+		if doctorFix {
+			dir, err := cfg.BackupDir(path)
+			if err != nil {
+				return err
+			}
+			dest := filepath.Join(dir, "pre-doctor-"+time.Now().Format("20060102-150405")+".db")
+			if err := db.Backup(dbh, dest); err != nil {
+				return err
+			}
+			fmt.Println(ui.DefaultTheme.Hint.Render("backup written to " + dest))
+		}
+
+		report, err := doctor.Run(dbh, doctor.Options{Fix: doctorFix, MaxTimer: doctorMaxTimer})
+		if err != nil {
+			return err
+		}
+
+		byCheck := map[string][]doctor.Finding{}
+		for _, f := range report.Findings {
+			byCheck[f.Check] = append(byCheck[f.Check], f)
+		}
+		var open int
+		for _, c := range report.Checks {
+			fs := byCheck[c]
+			if len(fs) == 0 {
+				fmt.Println(ui.DefaultTheme.Success.Render("  ok   ") + ui.DefaultTheme.Value.Render(c))
+				continue
+			}
+			fmt.Println(ui.DefaultTheme.Error.Render(fmt.Sprintf("  %-4d ", len(fs))) + ui.DefaultTheme.Value.Render(c))
+			for _, f := range fs {
+				fmt.Println("         " + f.Problem)
+				if f.Fixed {
+					fmt.Println(ui.DefaultTheme.Success.Render("         fixed: ") + f.Action)
+				} else {
+					fmt.Println(ui.DefaultTheme.Hint.Render("         fix: " + f.Action))
+					open++
+				}
+			}
+		}
+		switch {
+		case len(report.Findings) == 0:
+			fmt.Println(ui.DefaultTheme.Success.Render("No problems found."))
+		case open == 0:
+			fmt.Println(ui.DefaultTheme.Success.Render(fmt.Sprintf("Repaired %d problem(s).", len(report.Findings))))
+		case !doctorFix:
+			fmt.Println(ui.DefaultTheme.Error.Render(fmt.Sprintf("%d problem(s) found; run pulse doctor --fix to repair.", open)))
+		default:
+			fmt.Println(ui.DefaultTheme.Error.Render(fmt.Sprintf("%d problem(s) need manual attention.", open)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Repair what can be repaired (a backup is taken first)")
+	doctorCmd.Flags().DurationVar(&doctorMaxTimer, "max-timer", 12*time.Hour, "Flag timers running longer than this")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editCategory  string
+	editProject   string
+	editText      string
+	editAddTags   []string
+	editRemoveTag []string
+	editBillable  bool
+)
+
+const editTimeLayout = "2006-01-02 15:04:05"
+
+// editCmd rewrites an entry, either through $EDITOR or with flags for scripted edits.
+var editCmd = &cobra.Command{
+	Use:   "edit <id>",
+	Short: "Edit an entry in $EDITOR (or with flags)",
+	Long: `Without flags the entry opens in $VISUAL/$EDITOR as a small document:
+
+	---
+	category: task
+	project: acme
+	tags: bug, prod
+	timestamp: 2025-09-28 14:05:00
+	duration: 1h30m
+	billable: yes
+	---
+	Entry text…
+
+Timestamps are in your configured timezone. duration is empty for untimed
+entries and "running" for an active timer. A new timestamp moves a timer
+with its pauses; a new duration replaces the pauses with one stretch of work.
+
+Examples:
+	pulse edit 42
+	pulse edit 42 --project acme --add-tag billable
+	pulse edit 42 --remove-tag wip --category task
+	pulse edit 42 --billable=false`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id %q", args[0])
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		e, err := st.GetEntry(id)
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("entry #%d not found", id)
+		}
+		if err != nil {
+			return err
+		}
+		if e.DeletedAt != nil {
+			return fmt.Errorf("entry #%d is in the trash (pulse trash restore %d)", id, id)
+		}
+
+		flags := cmd.Flags()
+		scripted := flags.Changed("category") || flags.Changed("project") || flags.Changed("text") ||
+			flags.Changed("add-tag") || flags.Changed("remove-tag") || flags.Changed("billable")
+
+		var updated model.Entry
+		if scripted {
+			updated = e
+			if flags.Changed("category") {
+				updated.Category = strings.TrimSpace(editCategory)
+			}
+			if flags.Changed("project") {
+				updated.Project = strings.TrimSpace(editProject)
+			}
+			if flags.Changed("text") {
+				updated.Text = editText
+			}
+			if flags.Changed("billable") {
+				updated.NonBillable = !editBillable
+			}
+			updated.Tags = db.ParseTags(db.JoinTags(append(slices.Clone(e.Tags), editAddTags...)))
+			for _, t := range db.ParseTags(strings.Join(editRemoveTag, ",")) {
+				updated.Tags = slices.DeleteFunc(updated.Tags, func(x string) bool { return x == t })
+			}
+		} else {
+			var changed bool
+			updated, changed, err = editInEditor(e)
+			if err != nil {
+				return err
+			}
+			if !changed {
+				fmt.Println("No changes.")
+				return nil
+			}
+		}
+		if err := validateEntry(updated); err != nil {
+			return err
+		}
+
+		if _, err := st.UpdateEntry(updated); err != nil {
+			return err
+		}
+		fmt.Printf("Entry #%d updated.\n", id)
+		if updated.Project != e.Project {
+			warnProject(st, updated.Project)
+		}
+		return nil
+	},
+}
+
+func init() {
+	editCmd.Flags().StringVarP(&editCategory, "category", "c", "", "Set category")
+	editCmd.Flags().StringVarP(&editProject, "project", "p", "", "Set project (empty to clear)")
+	editCmd.Flags().StringVar(&editText, "text", "", "Replace the entry text")
+	editCmd.Flags().StringSliceVar(&editAddTags, "add-tag", nil, "Add tag(s)")
+	editCmd.Flags().StringSliceVar(&editRemoveTag, "remove-tag", nil, "Remove tag(s)")
+	editCmd.Flags().BoolVar(&editBillable, "billable", true, "Whether the tracked time goes on invoices (--billable=false)")
+	rootCmd.AddCommand(editCmd)
+}
+
+func validateEntry(e model.Entry) error {
+	if strings.TrimSpace(e.Category) == "" {
+		return fmt.Errorf("category must not be empty")
+	}
+	if strings.TrimSpace(e.Text) == "" {
+		return fmt.Errorf("text must not be empty")
+	}
+	if e.StartedAt != nil && e.EndedAt != nil && e.EndedAt.Before(*e.StartedAt) {
+		return fmt.Errorf("duration must not be negative")
+	}
+	return nil
+}
+
+// editInEditor round-trips e through $EDITOR. On a parse error the temp file
+// is kept so the user's edits are not lost.
+func editInEditor(e model.Entry) (model.Entry, bool, error) {
+	loc := cfg.Location()
+	before := formatEntryDoc(e, loc)
+
+	f, err := os.CreateTemp("", fmt.Sprintf("pulse-%d-*.md", e.ID))
+	if err != nil {
+		return e, false, err
+	}
+	path := f.Name()
+	if _, err := f.WriteString(before); err != nil {
+		f.Close()
+		return e, false, err
+	}
+	if err := f.Close(); err != nil {
+		return e, false, err
+	}
+
+	if err := runEditor(path); err != nil {
+		os.Remove(path)
+		return e, false, err
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return e, false, err
+	}
+	if string(b) == before {
+		os.Remove(path)
+		return e, false, nil
+	}
+	updated, err := parseEntryDoc(string(b), e, loc)
+	if err != nil {
+		return e, false, fmt.Errorf("invalid entry: %w (your edits are saved in %s)", err, path)
+	}
+	os.Remove(path)
+	return updated, true, nil
+}
+
+func runEditor(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	parts := strings.Fields(editor)
+	c := exec.Command(parts[0], append(parts[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+func formatEntryDoc(e model.Entry, loc *time.Location) string {
+	dur := ""
+	switch {
+	case e.Running():
+		dur = "running"
+	case e.StartedAt != nil:
+		dur = e.Duration(time.Now()).Round(time.Second).String()
+	}
+	var b strings.Builder
+	b.WriteString("---\n")
+	fmt.Fprintf(&b, "category: %s\n", e.Category)
+	fmt.Fprintf(&b, "project: %s\n", e.Project)
+	fmt.Fprintf(&b, "tags: %s\n", strings.Join(e.Tags, ", "))
+	fmt.Fprintf(&b, "timestamp: %s\n", e.TS.In(loc).Format(editTimeLayout))
+	fmt.Fprintf(&b, "duration: %s\n", dur)
+	fmt.Fprintf(&b, "billable: %s\n", yesNo(!e.NonBillable))
+	b.WriteString("---\n")
+	b.WriteString(e.Text)
+	b.WriteString("\n")
+	return b.String()
+}
+
+// parseEntryDoc applies an edited document on top of orig.
+func parseEntryDoc(doc string, orig model.Entry, loc *time.Location) (model.Entry, error) {
+	e := orig
+	sc := bufio.NewScanner(strings.NewReader(doc))
+	if !sc.Scan() || strings.TrimSpace(sc.Text()) != "---" {
+		return e, fmt.Errorf("document must start with a --- line")
+	}
+	closed := false
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "---" {
+			closed = true
+			break
+		}
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		key, val, ok := strings.Cut(line, ":")
+		if !ok {
+			return e, fmt.Errorf("bad header line %q (want key: value)", line)
+		}
+		val = strings.TrimSpace(val)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "category":
+			e.Category = val
+		case "project":
+			e.Project = val
+		case "tags":
+			e.Tags = db.ParseTags(val)
+		case "timestamp":
+			t, err := time.ParseInLocation(editTimeLayout, val, loc)
+			if err != nil {
+				if t, err = time.Parse(time.RFC3339, val); err != nil {
+					return e, fmt.Errorf("timestamp %q: want YYYY-MM-DD HH:MM:SS", val)
+				}
+			}
+			// The header shows whole seconds; left as shown, the stored
+			// milliseconds stay.
+			if !t.Equal(orig.TS.Truncate(time.Second)) {
+				e.TS = t
+			}
+		case "duration":
+			if err := applyDuration(&e, orig, val); err != nil {
+				return e, err
+			}
+		case "billable":
+			switch strings.ToLower(val) {
+			case "yes", "true", "y", "":
+				e.NonBillable = false
+			case "no", "false", "n":
+				e.NonBillable = true
+			default:
+				return e, fmt.Errorf("billable %q: want yes or no", val)
+			}
+		default:
+			return e, fmt.Errorf("unknown header %q", strings.TrimSpace(key))
+		}
+	}
+	if !closed {
+		return e, fmt.Errorf("missing closing --- line")
+	}
+	var body []string
+	for sc.Scan() {
+		body = append(body, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		return e, err
+	}
+	e.Text = strings.TrimRight(strings.Join(body, "\n"), "\n ")
+
+	// Timed entries start at their timestamp; moving one moves its end and
+	// segments along.
+	if e.StartedAt != nil {
+		delta := e.TS.Sub(*e.StartedAt)
+		start := e.TS
+		e.StartedAt = &start
+		if e.EndedAt != nil {
+			end := e.EndedAt.Add(delta)
+			e.EndedAt = &end
+		}
+		var segments []model.Segment
+		for _, sg := range e.Segments {
+			moved := model.Segment{StartedAt: sg.StartedAt.Add(delta)}
+			if sg.EndedAt != nil {
+				end := sg.EndedAt.Add(delta)
+				moved.EndedAt = &end
+			}
+			segments = append(segments, moved)
+		}
+		e.Segments = segments
+	}
+	return e, nil
+}
+
+func applyDuration(e *model.Entry, orig model.Entry, val string) error {
+	switch strings.ToLower(val) {
+	case "":
+		e.StartedAt, e.EndedAt, e.Segments = nil, nil, nil
+	case "running":
+		if !orig.Running() {
+			return fmt.Errorf("only an active timer can have duration: running")
+		}
+	default:
+		d, err := time.ParseDuration(val)
+		if err != nil || d < 0 {
+			return fmt.Errorf("duration %q: want e.g. 45m, 1h30m", val)
+		}
+		if orig.StartedAt != nil && !orig.Running() && d == orig.Duration(time.Now()).Round(time.Second) {
+			return nil // as shown, so keep the exact times and segments
+		}
+		// A new duration is one stretch of work, without the pauses.
+		e.Segments = nil
+		start := e.TS
+		if orig.StartedAt != nil {
+			start = *orig.StartedAt
+		}
+		end := start.Add(d)
+		e.StartedAt, e.EndedAt = &start, &end
+	}
+	return nil
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
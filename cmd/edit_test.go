@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+// timed worked 9:00–9:45 on 2025-10-01.
+func timed() model.Entry {
+	at := func(h, m int) time.Time { return time.Date(2025, 10, 1, h, m, 0, 0, time.UTC) }
+	return model.Entry{
+		ID: 1, TS: at(9, 0), Category: "task", Text: "work", Project: "acme", Tags: []string{"a"},
+		StartedAt: ptr(at(9, 0)), EndedAt: ptr(at(9, 45)),
+	}
+}
+
+func TestParseEntryDoc(t *testing.T) {
+	orig := timed()
+	doc := formatEntryDoc(orig, time.UTC)
+	at := func(d, h, m int) *time.Time { return ptr(time.Date(2025, 10, d, h, m, 0, 0, time.UTC)) }
+
+	tests := []struct {
+		name, old, new string
+		want           func(e *model.Entry)
+	}{
+		{"unchanged", "", "", func(e *model.Entry) {}},
+		{"text", "work", "more work\n\nsecond line", func(e *model.Entry) { e.Text = "more work\n\nsecond line" }},
+		{"header", "project: acme\ntags: a", "project: \ntags: #b, c", func(e *model.Entry) { e.Project, e.Tags = "", []string{"b", "c"} }},
+		{"timestamp", "09:00:00", "08:30:00", func(e *model.Entry) { e.TS, e.StartedAt, e.EndedAt = *at(1, 8, 30), at(1, 8, 30), at(1, 9, 15) }},
+		{"duration", "duration: 45m0s", "duration: 1h", func(e *model.Entry) { e.EndedAt = at(1, 10, 0) }},
+		{"untimed", "duration: 45m0s", "duration: ", func(e *model.Entry) { e.StartedAt, e.EndedAt = nil, nil }},
+		{"billable", "billable: yes", "billable: no", func(e *model.Entry) { e.NonBillable = true }},
+	}
+	for _, tt := range tests {
+		want := timed()
+		tt.want(&want)
+		got, err := parseEntryDoc(strings.Replace(doc, tt.old, tt.new, 1), orig, time.UTC)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, want)
+		}
+	}
+
+	bad := []struct{ old, new string }{
+		{"---\ncategory", "category"},
+		{"\n---\nwork", "\nwork"},
+		{"project:", "client:"},
+		{"timestamp: 2025-10-01 09:00:00", "timestamp: tomorrow"},
+		{"duration: 45m0s", "duration: -5m"},
+		{"duration: 45m0s", "duration: running"},
+		{"billable: yes", "billable: maybe"},
+	}
+	for _, tt := range bad {
+		if _, err := parseEntryDoc(strings.Replace(doc, tt.old, tt.new, 1), orig, time.UTC); err == nil {
+			t.Errorf("%q → %q: want an error", tt.old, tt.new)
+		}
+	}
+}
+
+func TestParseEntryDocMilliseconds(t *testing.T) {
+	orig := timed()
+	ms := 250 * time.Millisecond
+	orig.TS, orig.StartedAt, orig.EndedAt = orig.TS.Add(ms), ptr(orig.StartedAt.Add(ms)), ptr(orig.EndedAt.Add(ms))
+	doc := formatEntryDoc(orig, time.UTC)
+
+	e, err := parseEntryDoc(strings.Replace(doc, "work", "more work", 1), orig, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.TS.Equal(orig.TS) || !e.StartedAt.Equal(*orig.StartedAt) || !e.EndedAt.Equal(*orig.EndedAt) {
+		t.Errorf("text edit moved the entry: ts %v, %v–%v", e.TS, e.StartedAt, e.EndedAt)
+	}
+
+	e, err = parseEntryDoc(strings.Replace(doc, "09:00:00", "09:10:00", 1), orig, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 10, 1, 9, 10, 0, 0, time.UTC); !e.TS.Equal(want) || !e.StartedAt.Equal(want) || e.Duration(time.Now()) != 45*time.Minute {
+		t.Errorf("moved entry: ts %v, %v–%v", e.TS, e.StartedAt, e.EndedAt)
+	}
+}
+
+// pausedTimer worked 9:00–9:20 and 9:40–10:00 on 2025-10-01.
+func pausedTimer() model.Entry {
+	at := func(h, m int) time.Time { return time.Date(2025, 10, 1, h, m, 0, 0, time.UTC) }
+	return model.Entry{
+		ID: 1, TS: at(9, 0), Category: "timer", Text: "work",
+		StartedAt: ptr(at(9, 0)), EndedAt: ptr(at(10, 0)),
+		Segments: []model.Segment{
+			{StartedAt: at(9, 0), EndedAt: ptr(at(9, 20))},
+			{StartedAt: at(9, 40), EndedAt: ptr(at(10, 0))},
+		},
+	}
+}
+
+func TestParseEntryDocSegments(t *testing.T) {
+	orig := pausedTimer()
+	doc := formatEntryDoc(orig, time.UTC)
+	if !strings.Contains(doc, "duration: 40m0s") {
+		t.Errorf("document shows the paused timer as\n%s", doc)
+	}
+
+	e, err := parseEntryDoc(strings.Replace(doc, "timestamp: 2025-10-01 09:00:00", "timestamp: 2025-10-02 08:00:00", 1), orig, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Segments) != 2 || !e.Segments[1].StartedAt.Equal(time.Date(2025, 10, 2, 8, 40, 0, 0, time.UTC)) {
+		t.Errorf("moved segments = %+v", e.Segments)
+	}
+	if got := e.Duration(time.Now()); got != 40*time.Minute {
+		t.Errorf("moved duration = %v, want 40m", got)
+	}
+	if !orig.Segments[1].StartedAt.Equal(time.Date(2025, 10, 1, 9, 40, 0, 0, time.UTC)) {
+		t.Error("editing changed the original segments")
+	}
+
+	e, err = parseEntryDoc(strings.Replace(doc, "duration: 40m0s", "duration: 1h30m", 1), orig, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Segments) != 0 || e.Duration(time.Now()) != 90*time.Minute || !e.EndedAt.Equal(time.Date(2025, 10, 1, 10, 30, 0, 0, time.UTC)) {
+		t.Errorf("new duration: segments %v, ended %v", e.Segments, e.EndedAt)
+	}
+
+	e, err = parseEntryDoc(strings.Replace(doc, "work", "more work", 1), orig, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.Segments) != 2 || e.Duration(time.Now()) != 40*time.Minute {
+		t.Errorf("text edit changed the time: %+v", e)
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/export"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportFormat   string
+	exportFile     string
+	exportSince    string
+	exportUntil    string
+	exportProject  string
+	exportCategory string
+	exportTags     string
+	exportAny      bool
+	exportRounding string
+)
+
+// exportFormats are the --format values, which double as file extensions.
+var exportFormats = []string{"csv", "json", "md", "ics"}
+
+// exportCmd writes entries for other tools: spreadsheets, calendars, notes.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export entries as CSV, JSON, Markdown or iCalendar",
+	Long: `Export entries, oldest first, to stdout or --file.
+
+Formats:
+	csv, json  the --output schema (see README)
+	md         a journal grouped by day
+	ics        timers and meetings as calendar events
+
+Without --format the file extension decides (.csv, .json, .md, .ics), then csv.
+
+A rounding policy (--rounding or rounding in the config) rounds the Markdown
+durations and day totals, which also keep the unrounded time. CSV and JSON
+rows carry it as rounded_seconds next to duration_seconds; a per-day policy
+only applies to the Markdown day totals.
+
+Examples:
+	pulse export --since "last week" --until "last week" -f week.csv
+	pulse export --format ics --project acme > acme.ics
+	pulse export --format md --since "this month" --rounding "6m up"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := strings.ToLower(exportFormat)
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(exportFile)), ".")
+			if format == "markdown" {
+				format = "md"
+			}
+			if !slices.Contains(exportFormats, format) {
+				format = "csv"
+			}
+		}
+		if !slices.Contains(exportFormats, format) {
+			return fmt.Errorf("unknown export format %q (want csv, json, md or ics)", exportFormat)
+		}
+
+		since, until, err := parseRange(exportSince, exportUntil, time.Time{})
+		if err != nil {
+			return err
+		}
+		r, err := roundingPolicy(cmd, exportRounding, cfg.RoundingPolicy())
+		if err != nil {
+			return err
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		entries, err := st.ListEntries(store.Filter{
+			Since:    since,
+			Until:    until,
+			Project:  exportProject,
+			Category: exportCategory,
+			Tags:     db.ParseTags(exportTags),
+			AnyTag:   exportAny,
+			Limit:    store.NoLimit,
+		})
+		if err != nil {
+			return err
+		}
+		slices.Reverse(entries)
+
+		var w io.Writer = os.Stdout
+		var f *os.File
+		if exportFile != "" && exportFile != "-" {
+			if f, err = os.Create(exportFile); err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+
+		now := time.Now()
+		switch format {
+		case "csv", "json":
+			err = output.Write(w, output.Format(format), exportRows(entries, r, now))
+		case "md":
+			err = export.Markdown(w, entries, cfg.Location(), cfg.DayStart(), r, now)
+		case "ics":
+			err = export.ICS(w, entries, now)
+		}
+		if err != nil {
+			return err
+		}
+		if f != nil {
+			if err := f.Close(); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "Exported %d %s to %s\n", len(entries), plural(len(entries), "entry", "entries"), exportFile)
+		}
+		return nil
+	},
+}
+
+// exportRows prepares entries for the CSV and JSON exports. A per-entry
+// rounding policy fills in rounded_seconds; per-day rounding has no
+// per-entry value, so it stays the tracked time.
+func exportRows(entries []model.Entry, r rounding.Policy, now time.Time) []output.Entry {
+	rows := make([]output.Entry, len(entries))
+	for i, e := range entries {
+		rows[i] = output.NewEntry(e, now)
+		if !r.PerDay {
+			rows[i].RoundedSeconds = int64(r.Round(e.Duration(now)) / time.Second)
+		}
+	}
+	return rows
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "csv|json|md|ics (default: from --file extension, else csv)")
+	exportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "Write to this file instead of stdout")
+	exportCmd.Flags().StringVar(&exportSince, "since", "", `Start: today, 7d, "last week", 2025-09-01… (default: everything)`)
+	exportCmd.Flags().StringVar(&exportUntil, "until", "", "End, same forms as --since; a bare day includes that day")
+	exportCmd.Flags().StringVarP(&exportProject, "project", "p", "", "Only this project")
+	exportCmd.Flags().StringVarP(&exportCategory, "category", "c", "", "Only this category")
+	exportCmd.Flags().StringVar(&exportTags, "tags", "", "Comma separated tags to require (exact match)")
+	exportCmd.Flags().BoolVar(&exportAny, "any", false, "Match entries having any of --tags instead of all")
+	exportCmd.Flags().StringVar(&exportRounding, "rounding", "", roundingUsage)
+	rootCmd.AddCommand(exportCmd)
+}
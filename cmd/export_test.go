@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+func TestExportRows(t *testing.T) {
+	now := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time { return time.Date(2025, 10, 1, h, m, 0, 0, time.UTC) }
+	entries := []model.Entry{
+		{ID: 1, TS: at(9, 0), Category: "timer", Text: "api, v2", Project: "acme", Tags: []string{"dev"}, StartedAt: ptr(at(9, 0)), EndedAt: ptr(at(9, 50))},
+		{ID: 2, TS: at(10, 0), Category: "note", Text: "call back"},
+		{ID: 3, TS: at(11, 0), Category: "timer", Text: "review", StartedAt: ptr(at(11, 0))},
+	}
+	tests := []struct {
+		format output.Format
+		policy string
+		want   string
+	}{
+		{output.CSV, "15m up", `id,ts,category,project,tags,text,started_at,ended_at,duration_seconds,running,billable,rounded_seconds,paused
+1,2025-10-01T09:00:00Z,timer,acme,dev,"api, v2",2025-10-01T09:00:00Z,2025-10-01T09:50:00Z,3000,false,true,3600,false
+2,2025-10-01T10:00:00Z,note,,,call back,,,0,false,true,0,false
+3,2025-10-01T11:00:00Z,timer,,,review,2025-10-01T11:00:00Z,,3600,true,true,3600,false
+`},
+		// Per-day rounding has no per-entry value.
+		{output.CSV, "15m up per day", `id,ts,category,project,tags,text,started_at,ended_at,duration_seconds,running,billable,rounded_seconds,paused
+1,2025-10-01T09:00:00Z,timer,acme,dev,"api, v2",2025-10-01T09:00:00Z,2025-10-01T09:50:00Z,3000,false,true,3000,false
+2,2025-10-01T10:00:00Z,note,,,call back,,,0,false,true,0,false
+3,2025-10-01T11:00:00Z,timer,,,review,2025-10-01T11:00:00Z,,3600,true,true,3600,false
+`},
+		{output.JSON, "", `[
+  {
+    "id": 1,
+    "ts": "2025-10-01T09:00:00Z",
+    "category": "timer",
+    "text": "api, v2",
+    "project": "acme",
+    "tags": [
+      "dev"
+    ],
+    "started_at": "2025-10-01T09:00:00Z",
+    "ended_at": "2025-10-01T09:50:00Z",
+    "duration_seconds": 3000,
+    "running": false,
+    "billable": true,
+    "rounded_seconds": 3000,
+    "paused": false
+  },
+  {
+    "id": 2,
+    "ts": "2025-10-01T10:00:00Z",
+    "category": "note",
+    "text": "call back",
+    "project": "",
+    "tags": [],
+    "started_at": null,
+    "ended_at": null,
+    "duration_seconds": 0,
+    "running": false,
+    "billable": true,
+    "rounded_seconds": 0,
+    "paused": false
+  },
+  {
+    "id": 3,
+    "ts": "2025-10-01T11:00:00Z",
+    "category": "timer",
+    "text": "review",
+    "project": "",
+    "tags": [],
+    "started_at": "2025-10-01T11:00:00Z",
+    "ended_at": null,
+    "duration_seconds": 3600,
+    "running": true,
+    "billable": true,
+    "rounded_seconds": 3600,
+    "paused": false
+  }
+]
+`},
+	}
+	for _, tt := range tests {
+		r, err := rounding.Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err := output.Write(&b, tt.format, exportRows(entries, r, now)); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("%s with %q:\n%s\nwant:\n%s", tt.format, tt.policy, got, tt.want)
+		}
+	}
+}
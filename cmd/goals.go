@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/notify"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/report"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// goalsCmd shows project budgets and the weekly goal with progress bars.
+var goalsCmd = &cobra.Command{
+	Use:   "goals [project]",
+	Short: "Time budgets and the weekly goal: used, remaining and progress",
+	Long: `Every project budget (pulse project add acme --budget 120h --weekly 20h)
+and the weekly goal (goals.weekly in the config) with the time used so far,
+what is left and a progress bar. Period budgets count the current week or
+month. Time is rounded like in reports (rounding in the config).
+
+After pulse stop, crossing one of goals.alert_at (default 80% and 100%) of a
+budget or goal sends a notification.
+
+Examples:
+	pulse goals
+	pulse goals acme -o json`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		only := ""
+		if len(args) == 1 {
+			only = args[0]
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if only != "" {
+			_, err := st.GetProject(only)
+			if errors.Is(err, store.ErrProjectNotFound) {
+				if s := similarProject(st, only); s != "" {
+					return fmt.Errorf("%s is not a registered project (did you mean %s?)", only, s)
+				}
+				return fmt.Errorf("%s is not a registered project (pulse project add %s --budget 120h)", only, only)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		now := time.Now().In(cfg.Location())
+		goals, err := loadGoals(st, only, 0, now)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case outFormat.Machine():
+			rows := make([]output.Goal, len(goals))
+			for i, g := range goals {
+				rows[i] = output.Goal{
+					Project:          g.Project,
+					Period:           g.Period,
+					BudgetSeconds:    int64(g.Budget / time.Second),
+					UsedSeconds:      int64(g.Used / time.Second),
+					RemainingSeconds: int64(g.Remaining() / time.Second),
+					Percent:          roundPercent(g.Percent()),
+				}
+				if !g.Since.IsZero() {
+					since := g.Since.UTC()
+					rows[i].Since = &since
+				}
+			}
+			return output.Write(os.Stdout, outFormat, rows)
+		case outFormat == output.Plain:
+			for _, g := range goals {
+				fmt.Printf("%s\t%s\t%s\t%s\t%s\t%.1f\n", goalName(g), g.Period, report.FormatHM(g.Used),
+					report.FormatHM(g.Budget), signedHM(g.Remaining()), g.Percent())
+			}
+			return nil
+		}
+
+		if len(goals) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("no budgets or goals yet (pulse project add acme --budget 120h --weekly 20h, goals.weekly in the config)"))
+			return nil
+		}
+		fmt.Println(ui.DefaultTheme.Title.Render("Goals"), ui.DefaultTheme.Hint.Render(now.Format("Mon Jan 2")))
+		for _, g := range goals {
+			style := ui.DefaultTheme.Success
+			switch p := g.Percent(); {
+			case p >= 100:
+				style = ui.DefaultTheme.Error
+			case p >= float64(alertLevel()):
+				style = ui.DefaultTheme.Warning
+			}
+			left := signedHM(g.Remaining()) + " left"
+			if g.Remaining() < 0 {
+				left = report.FormatHM(-g.Remaining()) + " over"
+			}
+			fmt.Printf("  %s %s %s %s %5.0f%%  %s\n",
+				ui.DefaultTheme.Value.Render(fmt.Sprintf("%-20s", goalName(g))),
+				ui.DefaultTheme.Label.Render(fmt.Sprintf("%-6s", g.Period)),
+				style.Render(ui.Bar(g.Percent(), 20)),
+				fmt.Sprintf("%7s / %-7s", report.FormatHM(g.Used), report.FormatHM(g.Budget)),
+				g.Percent(),
+				ui.DefaultTheme.Hint.Render(left))
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(goalsCmd)
+}
+
+// loadGoals measures the weekly goal and the budgets of every active project,
+// or only of the project named only (plus the weekly goal). The entry with id
+// skip is left out, which tells where goals stood before it.
+func loadGoals(st store.Store, only string, skip int64, now time.Time) ([]report.Goal, error) {
+	loc, dayStart, r := cfg.Location(), cfg.DayStart(), cfg.RoundingPolicy()
+	week, _, err := timeparse.Range("this week", now, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	month, _, err := timeparse.Range("this month", now, dayStart)
+	if err != nil {
+		return nil, err
+	}
+	without := func(entries []model.Entry) []model.Entry {
+		return slices.DeleteFunc(entries, func(e model.Entry) bool { return e.ID == skip })
+	}
+
+	var goals []report.Goal
+	if goal := cfg.WeeklyGoal(); goal > 0 {
+		entries, err := st.ListEntries(store.Filter{Since: week, Limit: store.NoLimit})
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, report.Goal{Period: "week", Since: week, Budget: goal,
+			Used: report.Used(without(entries), week, loc, dayStart, r, now)})
+	}
+	projects, err := st.Projects()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range projects {
+		if p.Archived() || p.Budget <= 0 && p.PeriodBudget <= 0 || only != "" && p.Name != only {
+			continue
+		}
+		f := store.Filter{Project: p.Name, Limit: store.NoLimit}
+		if p.Budget <= 0 {
+			f.Since = month
+			if week.Before(month) {
+				f.Since = week
+			}
+		}
+		entries, err := st.ListEntries(f)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, report.ProjectGoals(p.Project, without(entries), week, month, loc, dayStart, r, now)...)
+	}
+	return goals, nil
+}
+
+// notifyGoals sends a notification for every budget or goal that entry id
+// (of project, "" for none) pushed past one of goals.alert_at.
+func notifyGoals(st store.Store, id int64, project string) {
+	if len(cfg.Goals.AlertAt) == 0 || project == "" && cfg.WeeklyGoal() <= 0 {
+		return
+	}
+	now := time.Now().In(cfg.Location())
+	after, err := loadGoals(st, project, 0, now)
+	if err != nil {
+		return
+	}
+	before, err := loadGoals(st, project, id, now)
+	if err != nil || len(before) != len(after) {
+		return
+	}
+	for i, g := range after {
+		t, ok := g.Crossed(before[i].Used, cfg.Goals.AlertAt)
+		if !ok {
+			continue
+		}
+		msg := fmt.Sprintf("%s: %d%% of the %s budget used (%s / %s)", goalName(g), t, goalPeriod(g), report.FormatHM(g.Used), report.FormatHM(g.Budget))
+		if g.Project == "" {
+			msg = fmt.Sprintf("%d%% of this week's goal reached (%s / %s)", t, report.FormatHM(g.Used), report.FormatHM(g.Budget))
+		}
+		fmt.Println(ui.DefaultTheme.Warning.Render(msg))
+		_ = notify.Info("Pulse budget", msg)
+	}
+}
+
+// alertLevel is the lowest goals.alert_at, where bars turn to a warning.
+func alertLevel() int {
+	if len(cfg.Goals.AlertAt) == 0 {
+		return 100
+	}
+	return slices.Min(cfg.Goals.AlertAt)
+}
+
+func goalName(g report.Goal) string {
+	if g.Project == "" {
+		return "(all projects)"
+	}
+	return g.Project
+}
+
+func goalPeriod(g report.Goal) string {
+	switch g.Period {
+	case "week":
+		return "weekly"
+	case "month":
+		return "monthly"
+	}
+	return "total"
+}
+
+// signedHM is report.FormatHM with a minus sign for negative durations.
+func signedHM(d time.Duration) string {
+	if d < 0 {
+		return "-" + report.FormatHM(-d)
+	}
+	return report.FormatHM(d)
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/config"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/report"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+func TestLoadGoals(t *testing.T) {
+	saved := cfg
+	t.Cleanup(func() { cfg = saved })
+	cfg = config.Default()
+	cfg.Reminder.Timezone = "UTC"
+	cfg.Goals.Weekly = "10h"
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	for _, p := range []model.Project{
+		{Name: "acme", Budget: 10 * time.Hour, PeriodBudget: 5 * time.Hour, BudgetPeriod: "month"},
+		{Name: "beta", PeriodBudget: 2 * time.Hour, BudgetPeriod: "week"},
+		{Name: "old", Budget: time.Hour},
+		{Name: "free"},
+	} {
+		if _, err := st.SaveProject(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := st.ArchiveProject("old", true); err != nil {
+		t.Fatal(err)
+	}
+	var last int64
+	for _, w := range []struct {
+		month     time.Month
+		day, mins int
+		project   string
+	}{
+		{time.September, 30, 60, "acme"},
+		{time.October, 2, 60, "acme"},
+		{time.October, 7, 60, "beta"},
+		{time.October, 8, 30, ""},
+		{time.October, 8, 45, "old"},
+		{time.October, 7, 30, "acme"},
+	} {
+		start := time.Date(2025, w.month, w.day, 10, 0, 0, 0, time.UTC)
+		e, err := st.CreateEntry(model.Entry{TS: start, Category: "timer", Text: "work", Project: w.project,
+			StartedAt: ptr(start), EndedAt: ptr(start.Add(time.Duration(w.mins) * time.Minute))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		last = e.ID
+	}
+
+	now := time.Date(2025, 10, 8, 12, 0, 0, 0, time.UTC)
+	week, month := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC), time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC)
+	goal := func(project, period string, since time.Time, budget time.Duration, used int) report.Goal {
+		return report.Goal{Project: project, Period: period, Since: since, Budget: budget, Used: time.Duration(used) * time.Minute}
+	}
+	tests := []struct {
+		only string
+		skip int64
+		want []report.Goal
+	}{
+		{"", 0, []report.Goal{
+			goal("", "week", week, 10*time.Hour, 165),
+			goal("acme", "total", time.Time{}, 10*time.Hour, 150),
+			goal("acme", "month", month, 5*time.Hour, 90),
+			goal("beta", "week", week, 2*time.Hour, 60),
+		}},
+		// The entry being stopped is left out, to measure what it adds.
+		{"acme", last, []report.Goal{
+			goal("", "week", week, 10*time.Hour, 135),
+			goal("acme", "total", time.Time{}, 10*time.Hour, 120),
+			goal("acme", "month", month, 5*time.Hour, 60),
+		}},
+	}
+	for _, tt := range tests {
+		got, err := loadGoals(st, tt.only, tt.skip, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("loadGoals(%q, %d):\n%+v\nwant\n%+v", tt.only, tt.skip, got, tt.want)
+		}
+	}
+}
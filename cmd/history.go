@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd shows every recorded change to an entry as a field-by-field diff.
+var historyCmd = &cobra.Command{
+	Use:   "history <id>",
+	Short: "Show the revision history of an entry",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(strings.TrimPrefix(args[0], "#"), 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id %q", args[0])
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		revs, err := st.History(id)
+		if errors.Is(err, store.ErrNotFound) {
+			return fmt.Errorf("entry #%d not found", id)
+		}
+		if err != nil {
+			return err
+		}
+
+		loc := cfg.Location()
+		fmt.Println(ui.DefaultTheme.Title.Render(fmt.Sprintf("History of #%d", id)))
+		if len(revs) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("no changes since it was logged"))
+			return nil
+		}
+		for i, r := range revs {
+			fmt.Println(ui.DefaultTheme.Label.Render(fmt.Sprintf("Revision %d — %s", i+1, r.ChangedAt.In(loc).Format("2006-01-02 15:04:05"))))
+			for _, line := range diffEntries(r.Before, r.After, loc) {
+				fmt.Println("  " + line)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+var (
+	diffDel = lipgloss.NewStyle().Foreground(lipgloss.Color("#F38BA8"))
+	diffAdd = lipgloss.NewStyle().Foreground(lipgloss.Color("#A6E3A1"))
+)
+
+// diffEntries lists the fields that differ between a and b; text gets a line diff.
+func diffEntries(a, b model.Entry, loc *time.Location) []string {
+	var out []string
+	field := func(name, from, to string) {
+		if from == to {
+			return
+		}
+		out = append(out, fmt.Sprintf("%-10s %s → %s", name+":", diffDel.Render(orNone(from)), diffAdd.Render(orNone(to))))
+	}
+	stamp := func(t *time.Time) string {
+		if t == nil {
+			return ""
+		}
+		return t.In(loc).Format("2006-01-02 15:04:05")
+	}
+	dur := func(e model.Entry) string {
+		switch {
+		case e.StartedAt == nil:
+			return ""
+		case e.Paused():
+			return "paused (" + formatDuration(e.Duration(time.Now())) + ")"
+		case e.Running():
+			return "running"
+		}
+		return formatDuration(e.Duration(time.Now()))
+	}
+
+	field("timestamp", stamp(&a.TS), stamp(&b.TS))
+	field("category", a.Category, b.Category)
+	field("project", a.Project, b.Project)
+	field("tags", strings.Join(a.Tags, ", "), strings.Join(b.Tags, ", "))
+	field("duration", dur(a), dur(b))
+	field("started", stamp(a.StartedAt), stamp(b.StartedAt))
+	field("ended", stamp(a.EndedAt), stamp(b.EndedAt))
+	field("deleted", stamp(a.DeletedAt), stamp(b.DeletedAt))
+	field("billable", yesNo(!a.NonBillable), yesNo(!b.NonBillable))
+	if a.Text != b.Text {
+		out = append(out, "text:")
+		for _, l := range diffLines(strings.Split(a.Text, "\n"), strings.Split(b.Text, "\n")) {
+			out = append(out, "  "+l)
+		}
+	}
+	return out
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// diffLines is a minimal LCS line diff rendering "- old" / "+ new" / "  same".
+func diffLines(a, b []string) []string {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, diffDel.Render("- "+a[i]))
+			i++
+		default:
+			out = append(out, diffAdd.Render("+ "+b[j]))
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, diffDel.Render("- "+a[i]))
+	}
+	for ; j < len(b); j++ {
+		out = append(out, diffAdd.Render("+ "+b[j]))
+	}
+	return out
+}
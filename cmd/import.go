@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/importer"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	importFormat   string
+	importDryRun   bool
+	importProject  string
+	importCategory string
+	importTags     string
+)
+
+// importCmd loads history from pulse exports and other time trackers.
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import entries from CSV/JSON, Timewarrior, Toggl or Watson",
+	Long: `Import entries from a file ("-" for stdin) in one transaction.
+
+Formats:
+	csv          pulse export CSV, or any CSV with ts/start/end/duration/category/project/tags/text columns
+	json         pulse export JSON or -o ndjson output
+	timewarrior  timew export
+	toggl-csv    Toggl Track detailed report (times in your configured timezone)
+	watson       ~/.config/watson/frames or watson log --json
+
+Every imported record is fingerprinted, so importing the same file again
+only adds what is new. pulse undo removes the whole import.
+
+Examples:
+	pulse import history.csv --dry-run
+	timew export | pulse import - --format timewarrior --project acme
+	pulse import ~/.config/watson/frames --format watson`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		format := strings.ToLower(importFormat)
+		if format == "" {
+			format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+			if !slices.Contains([]string{"csv", "json"}, format) {
+				return fmt.Errorf("cannot tell the format of %s; pass --format %s", path, strings.Join(importer.Formats, "|"))
+			}
+		}
+
+		var r io.Reader = os.Stdin
+		if path != "-" {
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		recs, skipped, err := importer.Read(format, r, importer.Options{Loc: cfg.Location(), Category: importCategory})
+		if err != nil {
+			return err
+		}
+		extra := db.ParseTags(importTags)
+		for i := range recs {
+			if importProject != "" {
+				recs[i].Project = importProject
+			}
+			recs[i].Tags = db.ParseTags(db.JoinTags(append(recs[i].Tags, extra...)))
+		}
+
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		res, err := st.ImportEntries(recs, importDryRun)
+		if err != nil {
+			return err
+		}
+
+		verb := "Imported"
+		if importDryRun {
+			verb = "Would import"
+		}
+		fmt.Printf("%s %d %s", verb, res.Added, plural(res.Added, "entry", "entries"))
+		if res.Duplicates > 0 {
+			fmt.Printf(", %d already imported", res.Duplicates)
+		}
+		if len(skipped) > 0 {
+			fmt.Printf(", %d skipped", len(skipped))
+		}
+		fmt.Println(".")
+		for i, s := range skipped {
+			if i == 10 {
+				fmt.Println(ui.DefaultTheme.Hint.Render(fmt.Sprintf("  … and %d more", len(skipped)-i)))
+				break
+			}
+			fmt.Println(ui.DefaultTheme.Hint.Render(fmt.Sprintf("  record %d: %s", s.Record, s.Reason)))
+		}
+		return nil
+	},
+}
+
+func init() {
+	importCmd.Flags().StringVar(&importFormat, "format", "", "csv|json|timewarrior|toggl-csv|watson (default: from the file extension)")
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Show what would be imported without writing anything")
+	importCmd.Flags().StringVarP(&importProject, "project", "p", "", "Set this project on every imported entry")
+	importCmd.Flags().StringVarP(&importCategory, "category", "c", "timer", "Category for tracked time from other trackers")
+	importCmd.Flags().StringVarP(&importTags, "tags", "t", "", "Comma separated tags to add to every imported entry")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/invoice"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	invoiceProject  string
+	invoiceMonth    string
+	invoiceFormat   string
+	invoiceFile     string
+	invoiceRounding string
+	invoiceDryRun   bool
+)
+
+// invoiceCmd bills a project's tracked time for one month.
+var invoiceCmd = &cobra.Command{
+	Use:   "invoice",
+	Short: "Generate a Markdown or HTML invoice from a project's billable timers",
+	Long: `One line item per billable, finished timer of the project in the month,
+priced at the project's hourly rate (pulse project add acme --rate 120) or a
+tag's rate (pulse tags rate urgent 180). Entries logged with --non-billable
+are left out.
+
+Each invoice gets the next running number (invoice.prefix in the config,
+INV-0001, INV-0002…); generating the same project and month again keeps its
+number. --dry-run prints a draft without using up a number.
+
+Examples:
+	pulse invoice --project acme                          # last month
+	pulse invoice --project acme --month 2025-09 -f acme-sept.html
+	pulse invoice --project acme --rounding "6m up per day" --dry-run`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if invoiceProject == "" {
+			return fmt.Errorf("--project is required")
+		}
+		format := strings.ToLower(invoiceFormat)
+		if format == "" {
+			format = "md"
+			if ext := strings.ToLower(filepath.Ext(invoiceFile)); ext == ".html" || ext == ".htm" {
+				format = "html"
+			}
+		}
+		if format == "markdown" {
+			format = "md"
+		}
+		if format != "md" && format != "html" {
+			return fmt.Errorf("unknown invoice format %q (want md or html)", invoiceFormat)
+		}
+		r, err := roundingPolicy(cmd, invoiceRounding, cfg.InvoiceRounding())
+		if err != nil {
+			return err
+		}
+		start, end, err := invoiceMonthRange(invoiceMonth)
+		if err != nil {
+			return err
+		}
+
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		p, err := st.GetProject(invoiceProject)
+		if errors.Is(err, store.ErrProjectNotFound) {
+			return fmt.Errorf("%s is not a registered project (pulse project add %s --rate 120)", invoiceProject, invoiceProject)
+		}
+		if err != nil {
+			return err
+		}
+		if p.Currency == "" {
+			p.Currency = cfg.Invoice.Currency
+		}
+		tagRates, err := st.TagRates()
+		if err != nil {
+			return err
+		}
+		entries, err := st.ListEntries(store.Filter{Since: start, Until: end, Project: p.Name, Limit: store.NoLimit})
+		if err != nil {
+			return err
+		}
+		inv, running, err := invoice.Build(entries, p, tagRates, r, cfg.Location(), cfg.DayStart())
+		if err != nil {
+			return err
+		}
+		for _, e := range running {
+			fmt.Fprintln(os.Stderr, ui.DefaultTheme.Error.Render(fmt.Sprintf("warning: timer #%d is still running and is not on the invoice", e.ID)))
+		}
+		if len(inv.Lines) == 0 {
+			return fmt.Errorf("nothing billable for %s between %s and %s", p.Name,
+				start.Format("2006-01-02"), end.AddDate(0, 0, -1).Format("2006-01-02"))
+		}
+		inv.Start, inv.End, inv.From = start, end, cfg.Invoice.From
+		inv.Issued, inv.Number = time.Now().In(cfg.Location()), "DRAFT"
+		if !invoiceDryRun {
+			rec, err := st.IssueInvoice(store.Invoice{ProjectID: p.ID, Project: p.Name, Start: start, End: end, Total: inv.Total, Currency: inv.Currency}, cfg.Invoice.Prefix)
+			if err != nil {
+				return err
+			}
+			inv.Number, inv.Issued = rec.Number, rec.IssuedAt.In(cfg.Location())
+		}
+
+		var w io.Writer = os.Stdout
+		if invoiceFile != "" {
+			f, err := os.Create(invoiceFile)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		if format == "html" {
+			err = invoice.HTML(w, inv)
+		} else {
+			err = invoice.Markdown(w, inv)
+		}
+		if err != nil {
+			return err
+		}
+		if invoiceFile != "" {
+			fmt.Printf("Invoice %s for %s: %s h, %s %s → %s\n", inv.Number, p.Name,
+				invoice.Hours(inv.Billed), inv.Currency, invoice.Money(inv.Total), invoiceFile)
+		}
+		return nil
+	},
+}
+
+func init() {
+	invoiceCmd.Flags().StringVarP(&invoiceProject, "project", "p", "", "Project to bill (required)")
+	invoiceCmd.Flags().StringVar(&invoiceMonth, "month", "last month", `Month to bill: 2025-09, "this month" or "last month"`)
+	invoiceCmd.Flags().StringVar(&invoiceFormat, "format", "", "md|html (default: from the file extension, else md)")
+	invoiceCmd.Flags().StringVarP(&invoiceFile, "file", "f", "", "Write to this file instead of stdout")
+	invoiceCmd.Flags().StringVar(&invoiceRounding, "rounding", "", `Round billed time: "15m up", "6m nearest per day", none… (default: invoice.rounding, else rounding)`)
+	invoiceCmd.Flags().BoolVar(&invoiceDryRun, "dry-run", false, "Print a draft without issuing an invoice number")
+	rootCmd.AddCommand(invoiceCmd)
+}
+
+// invoiceMonthRange resolves --month to [start, end) in the configured
+// timezone, honouring day_starts_at.
+func invoiceMonthRange(s string) (time.Time, time.Time, error) {
+	loc, dayStart := cfg.Location(), cfg.DayStart()
+	if t, err := time.ParseInLocation("2006-01", strings.TrimSpace(s), loc); err == nil {
+		start := timeparse.At(t, dayStart)
+		return start, start.AddDate(0, 1, 0), nil
+	}
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "this month", "last month":
+	default:
+		return time.Time{}, time.Time{}, fmt.Errorf(`--month %q: want YYYY-MM, "this month" or "last month"`, s)
+	}
+	return timeparse.Range(s, time.Now().In(loc), dayStart)
+}
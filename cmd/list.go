@@ -8,62 +8,50 @@ import (
 	"time"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/ramanasai/pulse/internal/config"
 	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
 	"github.com/spf13/cobra"
 )
 
 var (
-	since string
-	limit int
+	since     string
+	listUntil string
+	limit     int
+	listTags  string
+	listAny   bool
 )
 
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List recent entries (timeline view)",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Load config to get timezone
-		cfg, _ := config.Load()
 		loc := cfg.Location()
 
-		dbh, err := db.Open()
+		st, err := openStore()
 		if err != nil {
 			return err
 		}
-		defer dbh.Close()
-
-		// Compute 'since' for query (UTC) and for display (local)
-		var sinceLocal time.Time
-		if strings.TrimSpace(since) == "" {
-			sinceLocal = time.Now().In(loc).Add(-24 * time.Hour)
-		} else {
-			// try parse as RFC3339 or RFC3339Nano and then localize
-			if t, err := time.Parse(time.RFC3339Nano, since); err == nil {
-				sinceLocal = t.In(loc)
-			} else if t2, err2 := time.Parse(time.RFC3339, since); err2 == nil {
-				sinceLocal = t2.In(loc)
-			} else {
-				sinceLocal = time.Now().In(loc).Add(-24 * time.Hour)
-			}
-		}
-		sinceForQuery := sinceLocal.UTC().Format(time.RFC3339)
+		defer st.Close()
 
-		if limit <= 0 || limit > 1000 {
-			limit = 200
+		sinceLocal, untilLocal, err := parseRange(since, listUntil, time.Now().In(loc).Add(-24*time.Hour))
+		if err != nil {
+			return err
 		}
-
-		q := `
-SELECT id, ts, category, COALESCE(project,''), COALESCE(tags,''), text
-FROM entries
-WHERE ts >= ?
-ORDER BY ts DESC
-LIMIT ?
-`
-		rows, err := dbh.Query(q, sinceForQuery, limit)
+		sinceLocal = sinceLocal.In(loc)
+
+		entries, err := st.ListEntries(store.Filter{
+			Since:  sinceLocal,
+			Until:  untilLocal,
+			Tags:   db.ParseTags(listTags),
+			AnyTag: listAny,
+			Limit:  limit,
+		})
 		if err != nil {
 			return err
 		}
-		defer rows.Close()
+		if done, err := writeEntries(entries); done || err != nil {
+			return err
+		}
 
 		// ---- styles ----
 		titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#A6E3A1"))
@@ -71,7 +59,7 @@ LIMIT ?
 		idStyle := lipgloss.NewStyle().Faint(true)
 		timeStyle := lipgloss.NewStyle().Faint(true)
 		sidebarStyle := lipgloss.NewStyle().PaddingRight(2)
-		projectStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("#89B4FA"))
+		colors := loadProjectColors(st)
 		tagsStyle := lipgloss.NewStyle().Faint(true).Foreground(lipgloss.Color("#CBA6F7"))
 		textStyle := lipgloss.NewStyle()
 
@@ -95,23 +83,10 @@ LIMIT ?
 		fmt.Println(header)
 		fmt.Println(sepStyle.Render(strings.Repeat("─", min(termWidth, 120))))
 
-		for rows.Next() {
-			var (
-				id                        int
-				ts, cat, proj, tags, text string
-			)
-			if err := rows.Scan(&id, &ts, &cat, &proj, &tags, &text); err != nil {
-				return err
-			}
-
-			// parse DB timestamp (UTC) and render in configured timezone, 12-hour
-			var parsed time.Time
-			if t, err := time.Parse(time.RFC3339Nano, ts); err == nil {
-				parsed = t
-			} else if t2, err2 := time.Parse(time.RFC3339, ts); err2 == nil {
-				parsed = t2
-			}
-			tstr := parsed.In(loc).Format("03:04 PM")
+		now := time.Now()
+		for _, e := range entries {
+			tstr := e.TS.In(loc).Format("03:04 PM")
+			cat := e.Category
 
 			dot := lipgloss.NewStyle().
 				Foreground(colorForCategory(cat)).
@@ -122,18 +97,28 @@ LIMIT ?
 				Render(dot + " " + timeStyle.Render(tstr))
 
 			// line one: [id] category project tags
-			meta := idStyle.Render(fmt.Sprintf("[%d]", id)) + "  " +
+			meta := idStyle.Render(fmt.Sprintf("[%d]", e.ID)) + "  " +
 				lipgloss.NewStyle().Bold(true).Foreground(colorForCategory(cat)).Render(cat)
 
-			if proj != "" {
-				meta += "  " + projectStyle.Render("["+proj+"]")
+			if e.Project != "" {
+				meta += "  " + projectStyle(colors, e.Project).Render("["+e.Project+"]")
+			}
+			if len(e.Tags) > 0 {
+				meta += "  " + tagsStyle.Render("#"+strings.Join(e.Tags, " #"))
 			}
-			if tags = strings.TrimSpace(tags); tags != "" {
-				meta += "  " + tagsStyle.Render("#"+strings.ReplaceAll(tags, ",", " #"))
+			if e.StartedAt != nil {
+				dur := formatDuration(e.Duration(now))
+				switch {
+				case e.Paused():
+					dur = "paused " + dur
+				case e.Running():
+					dur = "running " + dur
+				}
+				meta += "  " + timeStyle.Render(dur)
 			}
 
 			// text body
-			body := textStyle.Width(rightWidth).Render(strings.TrimSpace(text))
+			body := textStyle.Width(rightWidth).Render(strings.TrimSpace(e.Text))
 
 			right := lipgloss.JoinVertical(lipgloss.Left, meta, body)
 			line := lipgloss.JoinHorizontal(lipgloss.Top, left, right)
@@ -141,13 +126,16 @@ LIMIT ?
 			fmt.Println(line)
 			fmt.Println(sepStyle.Render(strings.Repeat("─", min(termWidth, 120))))
 		}
-		return rows.Err()
+		return nil
 	},
 }
 
 func init() {
-	listCmd.Flags().StringVar(&since, "since", "", "RFC3339 timestamp or empty for last 24h (interpreted in your configured timezone)")
+	listCmd.Flags().StringVar(&since, "since", "", `Start: today, yesterday, 7d, "last monday", "this week", 2025-09-01… (default: last 24h)`)
+	listCmd.Flags().StringVar(&listUntil, "until", "", "End, same forms as --since; a bare day includes that day (default: now)")
 	listCmd.Flags().IntVar(&limit, "limit", 200, "Max entries to show (default 200)")
+	listCmd.Flags().StringVar(&listTags, "tags", "", "Comma separated tags to require (exact match)")
+	listCmd.Flags().BoolVar(&listAny, "any", false, "Match entries having any of --tags instead of all")
 }
 
 func colorForCategory(cat string) lipgloss.Color {
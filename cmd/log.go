@@ -3,33 +3,112 @@ package cmd
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/ramanasai/pulse/internal/capture"
 	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/timeparse"
 	"github.com/spf13/cobra"
 )
 
 var (
-	category string
-	project  string
-	tags     string
+	category    string
+	project     string
+	tags        string
+	logAt       string
+	logFrom     string
+	logTo       string
+	logDuration time.Duration
+	logRaw      bool
+	logNoBill   bool
 )
 
 var logCmd = &cobra.Command{
 	Use:   "log [text]",
 	Short: "Add a quick log entry",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Add a quick log entry, stamped now unless told otherwise.
+
+Times are in your configured timezone: "15:00", "3pm", "yesterday 15:00",
+"monday 9:30", "2025-09-01 14:00".
+
+Inline tokens in the text fill in fields and are removed from it:
+	#tag  @project  +category  ~30m (duration)  ^yesterday_15:00 (time, "_" for spaces)
+Flags win over inline tokens. Use --raw (or inline_syntax: false in the
+config) to store the text as typed, or \#escape a single token.
+
+Examples:
+	pulse log "standup notes"
+	pulse log "fixed login redirect #bug @acme ~45m"
+	pulse log --at "yesterday 15:00" "call with vendor"
+	pulse log --from 9:30 --to 10:45 -c meeting "planning"
+	pulse log --duration 45m -c meeting "forgot to start a timer"`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbh, err := db.Open()
-		if err != nil {
+		flags := cmd.Flags()
+		now := time.Now().In(cfg.Location())
+		e := model.Entry{
+			Category:    category,
+			Text:        strings.Join(args, " "),
+			Project:     project,
+			Tags:        db.ParseTags(tags),
+			NonBillable: logNoBill,
+		}
+
+		var t logTimes
+		if flags.Changed("duration") && logDuration <= 0 {
+			return fmt.Errorf("--duration must be positive")
+		}
+		t.duration = logDuration
+		for _, f := range []struct {
+			name, val string
+			dst       **time.Time
+		}{{"at", logAt, &t.at}, {"from", logFrom, &t.from}, {"to", logTo, &t.to}} {
+			if !flags.Changed(f.name) {
+				continue
+			}
+			v, err := timeparse.Instant(f.val, now)
+			if err != nil {
+				return fmt.Errorf("--%s: %w", f.name, err)
+			}
+			*f.dst = &v
+		}
+
+		if cfg.InlineSyntax && !logRaw {
+			c := capture.Parse(e.Text, now)
+			e.Text = c.Text
+			e.Tags = db.ParseTags(db.JoinTags(append(e.Tags, c.Tags...)))
+			if c.Project != "" && !flags.Changed("project") {
+				e.Project = c.Project
+			}
+			if c.Category != "" && !flags.Changed("category") {
+				e.Category = c.Category
+			}
+			if c.At != nil && t.at == nil && t.from == nil && t.to == nil {
+				t.at = c.At
+			}
+			if c.Duration > 0 && t.duration == 0 {
+				t.duration = c.Duration
+			}
+		}
+		if strings.TrimSpace(e.Text) == "" {
+			return fmt.Errorf("entry text is empty once inline tokens are removed (use --raw to keep them)")
+		}
+		if err := t.apply(&e, now); err != nil {
 			return err
 		}
-		defer dbh.Close()
-		text := strings.Join(args, " ")
-		_, err = dbh.Exec(`INSERT INTO entries(category, text, project, tags) VALUES(?,?,?,?)`, category, text, project, tags)
+
+		st, err := openStore()
 		if err != nil {
 			return err
 		}
+		defer st.Close()
+
+		if _, err := st.CreateEntry(e); err != nil {
+			return err
+		}
 		fmt.Println("Saved.")
+		warnProject(st, e.Project)
 		return nil
 	},
 }
@@ -38,4 +117,68 @@ func init() {
 	logCmd.Flags().StringVarP(&category, "category", "c", "note", "Category: note|task|meeting|timer")
 	logCmd.Flags().StringVarP(&project, "project", "p", "", "Project name")
 	logCmd.Flags().StringVarP(&tags, "tags", "t", "", "Comma separated tags")
+	logCmd.Flags().StringVar(&logAt, "at", "", `When it happened, e.g. "yesterday 15:00"`)
+	logCmd.Flags().StringVar(&logFrom, "from", "", "Start of the tracked time, e.g. 9:30")
+	logCmd.Flags().StringVar(&logTo, "to", "", "End of the tracked time, e.g. 10:45")
+	logCmd.Flags().DurationVar(&logDuration, "duration", 0, "Tracked time, e.g. 45m (ends now unless --at/--from/--to is given)")
+	logCmd.Flags().BoolVar(&logNoBill, "non-billable", false, "Keep the tracked time off invoices")
+	logCmd.Flags().BoolVar(&logRaw, "raw", false, "Store the text as typed, without parsing #tag @project +category ~duration ^time")
+}
+
+// logTimes holds the requested timing of a log entry; nil/zero means not given.
+type logTimes struct {
+	at, from, to *time.Time
+	duration     time.Duration
+}
+
+// apply fills e.TS and, for tracked time, StartedAt/EndedAt.
+func (t logTimes) apply(e *model.Entry, now time.Time) error {
+	hasDur := t.duration > 0
+	switch {
+	case t.at != nil && (t.from != nil || t.to != nil):
+		return fmt.Errorf("--at cannot be combined with --from/--to")
+	case t.from != nil && t.to != nil && hasDur:
+		return fmt.Errorf("give at most two of --from, --to and --duration")
+	case t.to != nil && t.from == nil && !hasDur:
+		return fmt.Errorf("--to needs --from or --duration")
+	}
+
+	if t.at != nil {
+		at := *t.at
+		e.TS = at
+		if hasDur {
+			end := at.Add(t.duration)
+			e.StartedAt, e.EndedAt = &at, &end
+		}
+		return nil
+	}
+	if t.from == nil && t.to == nil && !hasDur {
+		return nil
+	}
+
+	var start, end time.Time
+	switch {
+	case t.from != nil:
+		start = *t.from
+		switch {
+		case t.to != nil:
+			end = *t.to
+		case hasDur:
+			end = start.Add(t.duration)
+		default:
+			end = now
+		}
+	case t.to != nil:
+		end = *t.to
+		start = end.Add(-t.duration)
+	default:
+		end = now
+		start = end.Add(-t.duration)
+	}
+	if !end.After(start) {
+		return fmt.Errorf("end time %s is not after start %s", end.Format("2006-01-02 15:04"), start.Format("2006-01-02 15:04"))
+	}
+	e.TS = start
+	e.StartedAt, e.EndedAt = &start, &end
+	return nil
 }
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// writeEntries prints entries in the --output format. It reports false for
+// table output, which each command renders itself.
+func writeEntries(entries []model.Entry) (bool, error) {
+	now := time.Now()
+	switch {
+	case outFormat.Machine():
+		rows := make([]output.Entry, len(entries))
+		for i, e := range entries {
+			rows[i] = output.NewEntry(e, now)
+		}
+		return true, output.Write(os.Stdout, outFormat, rows)
+	case outFormat == output.Plain:
+		for _, e := range entries {
+			fmt.Println(plainEntry(e, now))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// writeSearchResults is writeEntries for search results.
+func writeSearchResults(results []store.SearchResult) (bool, error) {
+	now := time.Now()
+	switch {
+	case outFormat.Machine():
+		rows := make([]output.SearchResult, len(results))
+		for i, r := range results {
+			rows[i] = output.SearchResult{Entry: output.NewEntry(r.Entry, now), Snippet: r.Snippet, Rank: r.Rank}
+		}
+		return true, output.Write(os.Stdout, outFormat, rows)
+	case outFormat == output.Plain:
+		for _, r := range results {
+			fmt.Println(plainEntry(r.Entry, now))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// writeTotals is writeEntries for summary totals.
+func writeTotals(totals []store.CategoryTotal) (bool, error) {
+	switch {
+	case outFormat.Machine():
+		rows := make([]output.CategoryTotal, len(totals))
+		for i, t := range totals {
+			rows[i] = output.CategoryTotal{Category: t.Category, Count: t.Count, DurationSeconds: int64(t.Duration / time.Second)}
+		}
+		return true, output.Write(os.Stdout, outFormat, rows)
+	case outFormat == output.Plain:
+		for _, t := range totals {
+			fmt.Printf("%s\t%d\t%s\n", t.Category, t.Count, formatDuration(t.Duration))
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// plainEntry is one tab-separated line: id, local time, category, project,
+// tags, duration and text (newlines folded to spaces).
+func plainEntry(e model.Entry, now time.Time) string {
+	dur := ""
+	if e.StartedAt != nil {
+		dur = formatDuration(e.Duration(now))
+	}
+	return strings.Join([]string{
+		fmt.Sprint(e.ID),
+		e.TS.In(cfg.Location()).Format("2006-01-02 15:04"),
+		e.Category,
+		e.Project,
+		strings.Join(e.Tags, ","),
+		dur,
+		strings.Join(strings.Fields(e.Text), " "),
+	}, "\t")
+}
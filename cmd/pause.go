@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/spf13/cobra"
+)
+
+var (
+	pauseID     int64
+	resumeID    int64
+	resumeMulti bool
+)
+
+// pauseCmd takes a break from a timer without stopping it; the pause does
+// not count toward its duration.
+var pauseCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "Pause the active timer (pulse resume continues it)",
+	Long: `Pause a running timer for a break or an interruption. The time until
+pulse resume is left out of its duration; pulse stop on a paused timer ends
+it when it was paused. A paused timer does not block pulse start.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		e, err := st.PauseTimer(pauseID)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			return fmt.Errorf("timer #%d not found", pauseID)
+		case errors.Is(err, store.ErrNotRunning):
+			return fmt.Errorf("timer #%d is not active", pauseID)
+		case errors.Is(err, store.ErrPaused):
+			return fmt.Errorf("timer #%d is already paused", e.ID)
+		case errors.Is(err, store.ErrNoTimers):
+			return fmt.Errorf("no running timer to pause")
+		case err != nil:
+			return err
+		}
+		fmt.Printf("Timer #%d paused at %s (%s tracked)\n", e.ID, time.Now().In(cfg.Location()).Format(time.Kitchen), e.Duration(time.Now()).Round(time.Second))
+		return nil
+	},
+}
+
+// resumeCmd continues a paused timer with a new segment.
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume a paused timer",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		e, err := st.ResumeTimer(resumeID, resumeMulti)
+		switch {
+		case errors.Is(err, store.ErrNotFound):
+			return fmt.Errorf("timer #%d not found", resumeID)
+		case errors.Is(err, store.ErrNotRunning):
+			return fmt.Errorf("timer #%d is not active", resumeID)
+		case errors.Is(err, store.ErrNotPaused):
+			return fmt.Errorf("timer #%d is not paused", e.ID)
+		case errors.Is(err, store.ErrNoTimers):
+			return fmt.Errorf("no paused timer to resume")
+		case errors.Is(err, store.ErrTimerRunning):
+			return fmt.Errorf("another timer is active (pause or stop it, or use --allow-multiple)")
+		case err != nil:
+			return err
+		}
+		fmt.Printf("Timer #%d resumed at %s (%s tracked so far)\n", e.ID, time.Now().In(cfg.Location()).Format(time.Kitchen), e.Duration(time.Now()).Round(time.Second))
+		return nil
+	},
+}
+
+func init() {
+	pauseCmd.Flags().Int64VarP(&pauseID, "id", "i", 0, "Specific timer id to pause")
+	resumeCmd.Flags().Int64VarP(&resumeID, "id", "i", 0, "Specific timer id to resume")
+	resumeCmd.Flags().BoolVar(&resumeMulti, "allow-multiple", false, "Resume even if another timer is active")
+	rootCmd.AddCommand(pauseCmd, resumeCmd)
+}
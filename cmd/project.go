@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/report"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectClient string
+	projectColor  string
+	projectRate   float64
+	projectCurr   string
+	projectBudget time.Duration
+	projectWeekly time.Duration
+	projectMonth  time.Duration
+	projectAll    bool
+	projectUndo   bool
+)
+
+var projectCmd = &cobra.Command{
+	Use:     "project",
+	Aliases: []string{"projects"},
+	Short:   "Manage registered projects",
+	Long: `Projects are registered so typos do not create phantom projects, and to
+hold a client name and a color for list, search and the TUI. Logging to an
+unregistered or archived project prints a warning.`,
+}
+
+var projectAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Register a project, or change its client, color, rate or budgets",
+	Example: `  pulse project add acme --client "ACME Corp" --color "#F38BA8"
+  pulse project add acme --color 208
+  pulse project add acme --rate 120 --currency EUR
+  pulse project add acme --budget 120h --weekly 20h`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if projectColor != "" && !validColor(projectColor) {
+			return fmt.Errorf("--color %q: want #RGB, #RRGGBB or an ANSI color number 0-255", projectColor)
+		}
+		if projectRate < 0 {
+			return fmt.Errorf("--rate must not be negative")
+		}
+		if projectBudget < 0 || projectWeekly < 0 || projectMonth < 0 {
+			return fmt.Errorf("budgets must not be negative")
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		p, err := st.GetProject(args[0])
+		exists := err == nil
+		if err != nil && !errors.Is(err, store.ErrProjectNotFound) {
+			return err
+		}
+		p.Name = args[0]
+		flags := cmd.Flags()
+		if flags.Changed("client") || !exists {
+			p.Client = strings.TrimSpace(projectClient)
+		}
+		if flags.Changed("color") || !exists {
+			p.Color = projectColor
+		}
+		if flags.Changed("rate") || !exists {
+			p.Rate = projectRate
+		}
+		if flags.Changed("currency") || !exists {
+			p.Currency = strings.ToUpper(strings.TrimSpace(projectCurr))
+		}
+		if flags.Changed("budget") || !exists {
+			p.Budget = projectBudget
+		}
+		switch {
+		case flags.Changed("weekly") && flags.Changed("monthly"):
+			return fmt.Errorf("a project has either a --weekly or a --monthly budget")
+		case flags.Changed("weekly"):
+			p.PeriodBudget, p.BudgetPeriod = projectWeekly, "week"
+		case flags.Changed("monthly"):
+			p.PeriodBudget, p.BudgetPeriod = projectMonth, "month"
+		}
+		if p, err = st.SaveProject(p); err != nil {
+			return err
+		}
+		if exists {
+			fmt.Printf("Updated project %s.\n", p.Name)
+		} else {
+			fmt.Printf("Added project %s.\n", p.Name)
+		}
+		return nil
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List projects with their entries and tracked time",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		all, err := st.Projects()
+		if err != nil {
+			return err
+		}
+		var projects []store.ProjectUsage
+		for _, p := range all {
+			if projectAll || !p.Archived() {
+				projects = append(projects, p)
+			}
+		}
+
+		switch {
+		case outFormat.Machine():
+			rows := make([]output.Project, len(projects))
+			for i, p := range projects {
+				rows[i] = output.Project{Project: p.Project, Registered: p.Registered, Entries: p.Entries,
+					DurationSeconds: int64(p.Duration / time.Second), BudgetSeconds: int64(p.Budget / time.Second),
+					PeriodBudgetSeconds: int64(p.PeriodBudget / time.Second)}
+			}
+			return output.Write(os.Stdout, outFormat, rows)
+		case outFormat == output.Plain:
+			for _, p := range projects {
+				fmt.Printf("%s\t%s\t%s\t%d\t%s\n", p.Name, p.Client, projectStatus(p), p.Entries, report.FormatHM(p.Duration))
+			}
+			return nil
+		}
+
+		if len(projects) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("no projects yet (pulse project add <name>)"))
+			return nil
+		}
+		colors := projectColors(all)
+		for _, p := range projects {
+			name := projectStyle(colors, p.Name).Render(fmt.Sprintf("%-20s", p.Name))
+			line := fmt.Sprintf("  %s %-20s %5d items %8s", name, p.Client, p.Entries, report.FormatHM(p.Duration))
+			if p.Rate > 0 {
+				line += "  " + ui.DefaultTheme.Hint.Render(strings.TrimSpace(fmt.Sprintf("rate %g/h %s", p.Rate, p.Currency)))
+			}
+			if s := projectStatus(p); s != "" {
+				line += "  " + ui.DefaultTheme.Hint.Render(s)
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a project and move its entries",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		n, err := st.RenameProject(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Renamed %s to %s (%d %s).\n", args[0], args[1], n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+var projectMergeCmd = &cobra.Command{
+	Use:     "merge <from...> <into>",
+	Short:   "Move every entry of one or more projects into another",
+	Example: `  pulse project merge acmee acme-corp acme`,
+	Args:    cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		from, into := args[:len(args)-1], args[len(args)-1]
+		n, err := st.MergeProjects(from, into)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Merged %s into %s (%d %s).\n", strings.Join(from, ", "), into, n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+var projectArchiveCmd = &cobra.Command{
+	Use:   "archive <name...>",
+	Short: "Hide finished projects from project list (--undo to bring them back)",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		for _, name := range args {
+			if err := st.ArchiveProject(name, !projectUndo); err != nil {
+				return err
+			}
+		}
+		verb := "Archived"
+		if projectUndo {
+			verb = "Unarchived"
+		}
+		fmt.Printf("%s %s.\n", verb, strings.Join(args, ", "))
+		return nil
+	},
+}
+
+func init() {
+	projectAddCmd.Flags().StringVar(&projectClient, "client", "", "Client name")
+	projectAddCmd.Flags().StringVar(&projectColor, "color", "", `Color for list, search and the TUI: "#F38BA8" or an ANSI number`)
+	projectAddCmd.Flags().Float64Var(&projectRate, "rate", 0, "Hourly rate for invoices (0 to clear)")
+	projectAddCmd.Flags().DurationVar(&projectBudget, "budget", 0, "Total time budget, e.g. 120h (0 to clear)")
+	projectAddCmd.Flags().DurationVar(&projectWeekly, "weekly", 0, "Time budget per week, e.g. 20h (0 to clear)")
+	projectAddCmd.Flags().DurationVar(&projectMonth, "monthly", 0, "Time budget per month, e.g. 80h (0 to clear)")
+	projectAddCmd.Flags().StringVar(&projectCurr, "currency", "", "Invoice currency, e.g. EUR (default: invoice.currency from the config)")
+	projectListCmd.Flags().BoolVarP(&projectAll, "all", "a", false, "Include archived projects")
+	projectArchiveCmd.Flags().BoolVar(&projectUndo, "undo", false, "Unarchive instead")
+	projectCmd.AddCommand(projectAddCmd, projectListCmd, projectRenameCmd, projectMergeCmd, projectArchiveCmd)
+	rootCmd.AddCommand(projectCmd)
+}
+
+func projectStatus(p store.ProjectUsage) string {
+	switch {
+	case !p.Registered:
+		return "unregistered"
+	case p.Archived():
+		return "archived"
+	}
+	return ""
+}
+
+var colorRe = regexp.MustCompile(`^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6}|[0-9]{1,2}|1[0-9]{2}|2[0-4][0-9]|25[0-5])$`)
+
+func validColor(s string) bool {
+	return colorRe.MatchString(s)
+}
+
+// projectColors maps project names to their configured colors.
+func projectColors(projects []store.ProjectUsage) map[string]string {
+	colors := map[string]string{}
+	for _, p := range projects {
+		if p.Color != "" {
+			colors[p.Name] = p.Color
+		}
+	}
+	return colors
+}
+
+// loadProjectColors is projectColors straight from the store. Colors are
+// cosmetic, so a failure just means the default color.
+func loadProjectColors(st store.Store) map[string]string {
+	projects, _ := st.Projects()
+	return projectColors(projects)
+}
+
+func projectStyle(colors map[string]string, name string) lipgloss.Style {
+	c, ok := colors[name]
+	if !ok {
+		c = ui.ProjectColor
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color(c))
+}
+
+// warnProject tells the user on stderr when name is not a registered project
+// or has been archived. Entries are saved either way.
+func warnProject(st store.Store, name string) {
+	if name == "" {
+		return
+	}
+	p, err := st.GetProject(name)
+	switch {
+	case errors.Is(err, store.ErrProjectNotFound):
+		msg := fmt.Sprintf("warning: %q is not a registered project", name)
+		if s := similarProject(st, name); s != "" {
+			msg += fmt.Sprintf(" (did you mean %q?)", s)
+		} else {
+			msg += " (pulse project add " + name + ")"
+		}
+		fmt.Fprintln(os.Stderr, ui.DefaultTheme.Error.Render(msg))
+	case err == nil && p.Archived():
+		fmt.Fprintln(os.Stderr, ui.DefaultTheme.Error.Render(
+			fmt.Sprintf("warning: project %q is archived (pulse project archive --undo %s)", name, name)))
+	}
+}
+
+// similarProject finds a registered project whose name differs from name
+// only in case, spacing or punctuation.
+func similarProject(st store.Store, name string) string {
+	fold := func(s string) string {
+		return strings.Map(func(r rune) rune {
+			if r == ' ' || r == '-' || r == '_' || r == '.' {
+				return -1
+			}
+			return r
+		}, strings.ToLower(s))
+	}
+	projects, _ := st.Projects()
+	for _, p := range projects {
+		if p.Registered && fold(p.Name) == fold(name) {
+			return p.Name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/report"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	reportRange    string
+	reportSince    string
+	reportUntil    string
+	reportGroupBy  string
+	reportProject  string
+	reportCategory string
+	reportTags     string
+	reportAny      bool
+	reportRounding string
+)
+
+// reportCmd totals time over a period, nested by project, tag, category and day.
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Time totals over a period, grouped by project, tag, category or day",
+	Long: `Totals for a period, nested in --group-by order, with hours:minutes and
+each group's share of the grand total. An entry with several tags counts
+toward each of them.
+
+With a rounding policy (--rounding or rounding in the config) every total
+is rounded, per entry or per day, and the unrounded total is shown below.
+
+--range takes week, month, today, yesterday, "last week", "last month", 30d,
+2025-09-01… or custom together with --since/--until.
+
+Examples:
+	pulse report                                   # this week by project
+	pulse report --range "last month" --project acme --group-by tag
+	pulse report --range month --group-by project,day
+	pulse report --range custom --since 2025-09-01 --until 2025-09-15 -o csv
+	pulse report --range "last month" --group-by project,day --rounding "15m up per day"`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		by, err := report.ParseGroupBy(reportGroupBy)
+		if err != nil {
+			return err
+		}
+		since, until, err := reportPeriod()
+		if err != nil {
+			return err
+		}
+		r, err := roundingPolicy(cmd, reportRounding, cfg.RoundingPolicy())
+		if err != nil {
+			return err
+		}
+
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		entries, err := st.ListEntries(store.Filter{
+			Since:    since,
+			Until:    until,
+			Project:  reportProject,
+			Category: reportCategory,
+			Tags:     db.ParseTags(reportTags),
+			AnyTag:   reportAny,
+			Limit:    store.NoLimit,
+		})
+		if err != nil {
+			return err
+		}
+		loc := cfg.Location()
+		root := report.Build(entries, by, loc, cfg.DayStart(), r, time.Now())
+
+		switch outFormat {
+		case output.JSON:
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(map[string]any{
+				"since":    since.UTC(),
+				"until":    until.UTC(),
+				"group_by": by,
+				"rounding": r.String(),
+				"total":    reportNode(root, root.Duration),
+			})
+		case output.NDJSON, output.CSV, output.Plain:
+			return writeReportRows(root, by)
+		}
+
+		period := since.In(loc).Format("2006-01-02") + " → " + until.In(loc).Add(-time.Nanosecond).Format("2006-01-02")
+		fmt.Println(ui.DefaultTheme.Title.Render("Report"), ui.DefaultTheme.Value.Render(period),
+			ui.DefaultTheme.Hint.Render("by "+strings.Join(by, " › ")))
+		var walk func(g *report.Group, depth int)
+		walk = func(g *report.Group, depth int) {
+			for _, c := range g.Groups {
+				label := strings.Repeat("  ", depth) + c.Key
+				if by[depth] == "day" {
+					if d, err := time.Parse("2006-01-02", c.Key); err == nil {
+						label += d.Format(" Mon")
+					}
+				}
+				line := fmt.Sprintf("  %-32s %8s %6.1f%%  %4d items", label, report.FormatHM(c.Duration), report.Percent(c.Duration, root.Duration), c.Count)
+				if depth == 0 {
+					fmt.Println(ui.DefaultTheme.Value.Render(line))
+				} else {
+					fmt.Println(ui.DefaultTheme.Label.Render(line))
+				}
+				walk(c, depth+1)
+			}
+		}
+		walk(root, 0)
+		total := fmt.Sprintf("  %-32s %8s %6.1f%%  %4d items", "TOTAL", report.FormatHM(root.Duration), 100.0, root.Count)
+		fmt.Println(ui.DefaultTheme.Success.Render(total))
+		if !r.IsZero() {
+			fmt.Println(ui.DefaultTheme.Hint.Render(fmt.Sprintf("  rounded %s · tracked %s", r, report.FormatHM(root.Tracked))))
+		}
+		return nil
+	},
+}
+
+func init() {
+	reportCmd.Flags().StringVar(&reportRange, "range", "week", `week|month|custom or any period: today, "last week", 30d, 2025-09-01…`)
+	reportCmd.Flags().StringVar(&reportSince, "since", "", "Start for --range custom")
+	reportCmd.Flags().StringVar(&reportUntil, "until", "", "End for --range custom (default: now)")
+	reportCmd.Flags().StringVarP(&reportGroupBy, "group-by", "g", "project", "Comma separated levels: project, tag, category, day")
+	reportCmd.Flags().StringVarP(&reportProject, "project", "p", "", "Only this project")
+	reportCmd.Flags().StringVarP(&reportCategory, "category", "c", "", "Only this category")
+	reportCmd.Flags().StringVar(&reportTags, "tags", "", "Comma separated tags to require (exact match)")
+	reportCmd.Flags().BoolVar(&reportAny, "any", false, "Match entries having any of --tags instead of all")
+	reportCmd.Flags().StringVar(&reportRounding, "rounding", "", roundingUsage)
+	rootCmd.AddCommand(reportCmd)
+}
+
+// reportPeriod resolves --range (or --since/--until for custom) to [since, until).
+func reportPeriod() (time.Time, time.Time, error) {
+	now := time.Now().In(cfg.Location())
+	switch r := strings.ToLower(strings.TrimSpace(reportRange)); r {
+	case "custom":
+		if reportSince == "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--range custom needs --since")
+		}
+		since, until, err := parseRange(reportSince, reportUntil, time.Time{})
+		if err == nil && until.IsZero() {
+			until = now
+		}
+		return since, until, err
+	case "week", "month", "year":
+		r = "this " + r
+		fallthrough
+	default:
+		if reportSince != "" || reportUntil != "" {
+			return time.Time{}, time.Time{}, fmt.Errorf("--since/--until need --range custom")
+		}
+		since, until, err := timeparse.Range(r, now, cfg.DayStart())
+		if err != nil {
+			return since, until, fmt.Errorf("--range: %w", err)
+		}
+		if !until.After(since) {
+			return since, until, fmt.Errorf("--range %q is a point in time, not a period", reportRange)
+		}
+		return since, until, nil
+	}
+}
+
+type reportJSON struct {
+	Key            string       `json:"key,omitempty"`
+	Seconds        int64        `json:"seconds"`
+	Duration       string       `json:"duration"`
+	Percent        float64      `json:"percent"`
+	Count          int          `json:"count"`
+	TrackedSeconds int64        `json:"tracked_seconds"` // before rounding
+	Groups         []reportJSON `json:"groups,omitempty"`
+}
+
+func reportNode(g *report.Group, total time.Duration) reportJSON {
+	n := reportJSON{
+		Key:            g.Key,
+		Seconds:        int64(g.Duration / time.Second),
+		Duration:       report.FormatHM(g.Duration),
+		Percent:        roundPercent(report.Percent(g.Duration, total)),
+		Count:          g.Count,
+		TrackedSeconds: int64(g.Tracked / time.Second),
+	}
+	for _, c := range g.Groups {
+		n.Groups = append(n.Groups, reportNode(c, total))
+	}
+	return n
+}
+
+func roundPercent(p float64) float64 {
+	v, _ := strconv.ParseFloat(strconv.FormatFloat(p, 'f', 1, 64), 64)
+	return v
+}
+
+// writeReportRows flattens the tree to one row per leaf: a column per
+// --group-by level, then seconds, duration, percent, count and the seconds
+// tracked before rounding.
+func writeReportRows(root *report.Group, by []string) error {
+	header := append(by[:len(by):len(by)], "seconds", "duration", "percent", "count", "tracked_seconds")
+	type row struct {
+		keys []string
+		g    *report.Group
+	}
+	var rows []row
+	var walk func(g *report.Group, keys []string)
+	walk = func(g *report.Group, keys []string) {
+		if len(g.Groups) == 0 {
+			if g != root {
+				rows = append(rows, row{keys, g})
+			}
+			return
+		}
+		for _, c := range g.Groups {
+			walk(c, append(keys[:len(keys):len(keys)], c.Key))
+		}
+	}
+	walk(root, nil)
+
+	record := func(r row) []string {
+		return append(r.keys[:len(r.keys):len(r.keys)],
+			strconv.FormatInt(int64(r.g.Duration/time.Second), 10),
+			report.FormatHM(r.g.Duration),
+			strconv.FormatFloat(report.Percent(r.g.Duration, root.Duration), 'f', 1, 64),
+			strconv.Itoa(r.g.Count),
+			strconv.FormatInt(int64(r.g.Tracked/time.Second), 10))
+	}
+	switch outFormat {
+	case output.CSV:
+		w := csv.NewWriter(os.Stdout)
+		w.Write(header)
+		for _, r := range rows {
+			w.Write(record(r))
+		}
+		w.Flush()
+		return w.Error()
+	case output.Plain:
+		for _, r := range rows {
+			fmt.Println(strings.Join(record(r), "\t"))
+		}
+		return nil
+	}
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range rows {
+		obj := map[string]any{}
+		for i, k := range r.keys {
+			obj[by[i]] = k
+		}
+		obj["seconds"] = int64(r.g.Duration / time.Second)
+		obj["duration"] = report.FormatHM(r.g.Duration)
+		obj["percent"] = roundPercent(report.Percent(r.g.Duration, root.Duration))
+		obj["count"] = r.g.Count
+		obj["tracked_seconds"] = int64(r.g.Tracked / time.Second)
+		if err := enc.Encode(obj); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// rmCmd moves entries to the trash; `pulse trash restore` or `pulse undo` brings them back.
+var rmCmd = &cobra.Command{
+	Use:   "rm <id...>",
+	Short: "Move entries to the trash",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if err := st.DeleteEntries(ids); err != nil {
+			return err
+		}
+		fmt.Printf("Moved %d %s to the trash (pulse undo to revert).\n", len(ids), plural(len(ids), "entry", "entries"))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rmCmd)
+}
+
+// parseIDs parses entry ids given as arguments, dropping duplicates.
+func parseIDs(args []string) ([]int64, error) {
+	var ids []int64
+	for _, a := range args {
+		id, err := strconv.ParseInt(strings.TrimPrefix(a, "#"), 10, 64)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("invalid id %q", a)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
@@ -2,14 +2,23 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
-	"github.com/spf13/cobra"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 	"github.com/ramanasai/pulse/internal/config"
 	"github.com/ramanasai/pulse/internal/notify"
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/rounding"
 	"github.com/ramanasai/pulse/internal/schedule"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
+	"github.com/spf13/cobra"
 )
 
 var rootCmd = &cobra.Command{
@@ -19,11 +28,110 @@ var rootCmd = &cobra.Command{
 
 func Execute() error { return rootCmd.Execute() }
 
+var (
+	dbFlag      string
+	profileFlag string
+	outputFlag  string
+
+	// outFormat is the parsed --output flag.
+	outFormat = output.Table
+
+	// cfg is the loaded config (with the selected profile applied), set before any command runs.
+	cfg = config.Default()
+)
+
+// databasePath resolves the database file: --db, then $PULSE_DB, then the profile/config default.
+func databasePath() (string, error) {
+	if dbFlag != "" {
+		return dbFlag, nil
+	}
+	if p := os.Getenv("PULSE_DB"); p != "" {
+		return p, nil
+	}
+	return cfg.DatabasePath()
+}
+
+// openStore opens the data store shared by all commands.
+func openStore() (*store.SQLite, error) {
+	path, err := databasePath()
+	if err != nil {
+		return nil, err
+	}
+	st, err := store.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Backup.Auto {
+		autoBackup(path, st.DB())
+	}
+	return st, nil
+}
+
+// parseRange resolves --since/--until values (see timeparse.Range) in the
+// configured timezone. An empty --since falls back to defSince; an empty
+// --until leaves the range open.
+func parseRange(since, until string, defSince time.Time) (time.Time, time.Time, error) {
+	now := time.Now().In(cfg.Location())
+	start, end := defSince, time.Time{}
+	var err error
+	if strings.TrimSpace(since) != "" {
+		if start, err = timeparse.Since(since, now, cfg.DayStart()); err != nil {
+			return start, end, fmt.Errorf("--since: %w", err)
+		}
+	}
+	if strings.TrimSpace(until) != "" {
+		if end, err = timeparse.Until(until, now, cfg.DayStart()); err != nil {
+			return start, end, fmt.Errorf("--until: %w", err)
+		}
+	}
+	if !end.IsZero() && !end.After(start) {
+		return start, end, fmt.Errorf("--until must be after --since")
+	}
+	return start, end, nil
+}
+
+// roundingPolicy is the command's --rounding flag when given, else def
+// (from the config).
+func roundingPolicy(cmd *cobra.Command, flag string, def rounding.Policy) (rounding.Policy, error) {
+	if !cmd.Flags().Changed("rounding") {
+		return def, nil
+	}
+	return rounding.Parse(flag)
+}
+
+// roundingUsage is the help text shared by every --rounding flag.
+const roundingUsage = `Round tracked time: "15m up", "6m nearest per day", none… (default: rounding from the config)`
+
 func init() {
-	// Load config and start reminder if enabled
-	cfg, _ := config.Load()
+	rootCmd.PersistentFlags().StringVar(&dbFlag, "db", "", "Database file (default: $PULSE_DB, or the profile's database)")
+	rootCmd.PersistentFlags().StringVar(&profileFlag, "profile", "", "Config profile to use (default: $PULSE_PROFILE)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", "table", "Output format: table|plain|json|ndjson|csv")
 
 	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		f, err := output.ParseFormat(outputFlag)
+		if err != nil {
+			return err
+		}
+		outFormat = f
+		// Keep escape codes out of pipes and files
+		if !output.IsTerminal(os.Stdout) || os.Getenv("NO_COLOR") != "" {
+			lipgloss.SetColorProfile(termenv.Ascii)
+		}
+
+		// Load config (with profile overrides) and start reminder if enabled
+		name := profileFlag
+		if name == "" {
+			name = os.Getenv("PULSE_PROFILE")
+		}
+		loaded, err := config.LoadProfile(name)
+		if err != nil {
+			if name != "" {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "pulse: config:", err)
+		}
+		cfg = loaded
+
 		if cfg.Reminder.Enabled && os.Getenv("PULSE_NO_REMINDER") != "1" {
 			ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 			go func() {
@@ -1,7 +1,6 @@
 package cmd
 
 import (
-	"database/sql"
 	"fmt"
 	"os"
 	"strconv"
@@ -10,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +19,7 @@ var (
 	searchLimit int
 	searchProj  string
 	searchTags  string
+	searchAny   bool
 )
 
 // searchCmd performs an FTS5 search with highlighted snippets.
@@ -30,75 +31,46 @@ var searchCmd = &cobra.Command{
 			pulse search 'text:started tags:devops'   # target fields
 			pulse search 'incid*'                     # prefix search
 			pulse search "retro" --project devops     # combine filters
-			pulse search "error" --since 2025-09-01 --until 2025-09-28`,
+			pulse search "outage" --tags bug,prod --any  # either tag
+			pulse search "error" --since 2025-09-01 --until 2025-09-28
+			pulse search "deploy" --since "last month"`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		query := strings.Join(args, " ")
-		dbh, err := db.Open()
+		st, err := openStore()
 		if err != nil {
 			return err
 		}
-		defer dbh.Close()
+		defer st.Close()
 
-		if searchUntil == "" {
-			searchUntil = time.Now().Format(time.RFC3339)
+		f := store.Filter{
+			Project: searchProj,
+			Tags:    db.ParseTags(searchTags),
+			AnyTag:  searchAny,
+			Limit:   searchLimit,
 		}
-		if searchSince == "" {
-			// default: last 90 days for search
-			searchSince = time.Now().Add(-90 * 24 * time.Hour).Format(time.RFC3339)
-		}
-		if searchLimit <= 0 || searchLimit > 1000 {
-			searchLimit = 200
-		}
-
-		// Build filters
-		conds := []string{"e.ts BETWEEN ? AND ?"}
-		argsQ := []any{searchSince, searchUntil}
-
-		if strings.TrimSpace(searchProj) != "" {
-			conds = append(conds, "e.project = ?")
-			argsQ = append(argsQ, searchProj)
-		}
-		if t := strings.TrimSpace(searchTags); t != "" {
-			// simple contains-any: for each tag, require instr(tags,tag)>0
-			for _, tg := range strings.Split(t, ",") {
-				tg = strings.TrimSpace(tg)
-				if tg == "" {
-					continue
-				}
-				conds = append(conds, "instr(e.tags, ?) > 0")
-				argsQ = append(argsQ, tg)
-			}
+		// default: last 90 days for search
+		if f.Since, f.Until, err = parseRange(searchSince, searchUntil, time.Now().Add(-90*24*time.Hour)); err != nil {
+			return err
 		}
 
-		where := strings.Join(conds, " AND ")
-		sqlStr := `
-			SELECT e.id, e.ts, e.category, COALESCE(e.project,''), COALESCE(e.tags,''),
-				snippet(entries_fts, 0, '[', ']', '…', 8) AS snip,
-				bm25(entries_fts) AS rank
-			FROM entries_fts
-			JOIN entries e ON e.id = entries_fts.rowid
-			WHERE entries_fts MATCH ? AND ` + where + `
-			ORDER BY rank ASC, e.ts DESC
-			LIMIT ?
-			`
-		argsQ = append([]any{query}, argsQ...)
-		argsQ = append(argsQ, searchLimit)
-
-		rows, err := dbh.Query(sqlStr, argsQ...)
+		results, err := st.Search(query, f)
 		if err != nil {
 			return err
 		}
-		defer rows.Close()
+		if done, err := writeSearchResults(results); done || err != nil {
+			return err
+		}
 
 		// styles
 		title := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#A6E3A1"))
 		sep := lipgloss.NewStyle().Foreground(lipgloss.Color("#6C7086"))
 		meta := lipgloss.NewStyle().Faint(true)
 		cat := lipgloss.NewStyle().Bold(true)
-		proj := lipgloss.NewStyle().Foreground(lipgloss.Color("#89B4FA"))
+		colors := loadProjectColors(st)
 		tags := lipgloss.NewStyle().Faint(true).Foreground(lipgloss.Color("#CBA6F7"))
 		snip := lipgloss.NewStyle()
+		match := lipgloss.NewStyle().Bold(true)
 
 		colEnv := os.Getenv("COLUMNS")
 		w := 100
@@ -111,48 +83,52 @@ var searchCmd = &cobra.Command{
 		fmt.Println(title.Render("Search") + "  " + sep.Render("query: ") + query)
 		fmt.Println(sep.Render(strings.Repeat("─", min(w, 120))))
 
-		var count int
-		for rows.Next() {
-			var id int
-			var ts, c, p, tg, s sql.NullString
-			var rank float64
-			if err := rows.Scan(&id, &ts, &c, &p, &tg, &s, &rank); err != nil {
-				return err
-			}
-			tm := ts.String
-			if t, err := time.Parse(time.RFC3339Nano, tm); err == nil {
-				tm = t.Format("2006-01-02 15:04")
+		for _, r := range results {
+			line := meta.Render(fmt.Sprintf("[%d] %s", r.ID, r.TS.In(cfg.Location()).Format("2006-01-02 15:04"))) + "  " +
+				cat.Foreground(colorForCategory(r.Category)).Render(r.Category)
+			if r.Project != "" {
+				line += "  " + projectStyle(colors, r.Project).Render("["+r.Project+"]")
 			}
-			line := meta.Render(fmt.Sprintf("[%d] %s", id, tm)) + "  " +
-				cat.Foreground(colorForCategory(c.String)).Render(c.String)
-			if p.String != "" {
-				line += "  " + proj.Render("["+p.String+"]")
-			}
-			if strings.TrimSpace(tg.String) != "" {
-				line += "  " + tags.Render("#"+strings.ReplaceAll(tg.String, ",", " #"))
+			if len(r.Tags) > 0 {
+				line += "  " + tags.Render("#"+strings.Join(r.Tags, " #"))
 			}
 			fmt.Println(line)
 
-			// Bold the matched segments (we mark [ ... ] around matches in snippet())
-			highlight := s.String
-			highlight = strings.ReplaceAll(highlight, "[", "\x1b[1m")
-			highlight = strings.ReplaceAll(highlight, "]", "\x1b[0m")
-
-			fmt.Println(snip.Render("  " + highlight))
+			fmt.Println(snip.Render("  " + highlightSnippet(r.Snippet, match)))
 			fmt.Println(sep.Render(strings.Repeat("─", min(w, 120))))
-			count++
 		}
-		if count == 0 {
+		if len(results) == 0 {
 			fmt.Println(meta.Render("no results"))
 		}
-		return rows.Err()
+		return nil
 	},
 }
 
 func init() {
-	searchCmd.Flags().StringVar(&searchSince, "since", "", "RFC3339 start time (default: 90 days ago)")
-	searchCmd.Flags().StringVar(&searchUntil, "until", "", "RFC3339 end time (default: now)")
+	searchCmd.Flags().StringVar(&searchSince, "since", "", `Start: today, 7d, "last monday", "this week", 2025-09-01… (default: 90 days ago)`)
+	searchCmd.Flags().StringVar(&searchUntil, "until", "", "End, same forms as --since; a bare day includes that day (default: now)")
 	searchCmd.Flags().IntVar(&searchLimit, "limit", 200, "Max results (default 200)")
 	searchCmd.Flags().StringVar(&searchProj, "project", "", "Filter by project")
-	searchCmd.Flags().StringVar(&searchTags, "tags", "", "Comma separated tags to require")
+	searchCmd.Flags().StringVar(&searchTags, "tags", "", "Comma separated tags to require (exact match)")
+	searchCmd.Flags().BoolVar(&searchAny, "any", false, "Match entries having any of --tags instead of all")
+}
+
+// highlightSnippet renders the [ ]-marked matches of an FTS snippet in style.
+func highlightSnippet(snippet string, style lipgloss.Style) string {
+	var b strings.Builder
+	for {
+		open := strings.Index(snippet, "[")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(snippet[open:], "]")
+		if end < 0 {
+			break
+		}
+		b.WriteString(snippet[:open])
+		b.WriteString(style.Render(snippet[open+1 : open+end]))
+		snippet = snippet[open+end+1:]
+	}
+	b.WriteString(snippet)
+	return b.String()
 }
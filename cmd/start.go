@@ -1,11 +1,15 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
+	"github.com/ramanasai/pulse/internal/capture"
 	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -13,45 +17,71 @@ var (
 	startProject string
 	startTags    string
 	allowMulti   bool
+	startRaw     bool
+	startNoBill  bool
 )
 
 // startCmd begins a new active timer entry. By default it enforces a single active timer.
 var startCmd = &cobra.Command{
 	Use:   "start [text]",
 	Short: "Start a timer",
-	Args:  cobra.MinimumNArgs(1),
+	Long: `Start a timer. Inline #tag, @project and ^time (when it started, e.g.
+^9:30) tokens in the text are parsed as in pulse log; --raw disables this.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbh, err := db.Open()
-		if err != nil {
-			return err
+		e := model.Entry{
+			Text:        strings.Join(args, " "),
+			Project:     startProject,
+			Tags:        db.ParseTags(startTags),
+			NonBillable: startNoBill,
 		}
-		defer dbh.Close()
-
-		if !allowMulti {
-			var n int
-			if err := dbh.QueryRow(`SELECT count(1) FROM entries WHERE category='timer' AND instr(tags,'active')>0`).Scan(&n); err != nil {
-				return err
+		if cfg.InlineSyntax && !startRaw {
+			now := time.Now().In(cfg.Location())
+			c := capture.Parse(e.Text, now)
+			switch {
+			case c.Duration > 0:
+				return fmt.Errorf("a timer's duration comes from pulse stop; use pulse log ~30m for finished work")
+			case c.Category != "":
+				return fmt.Errorf("timers always have category timer; use pulse log +%s instead", c.Category)
+			case c.At != nil && c.At.After(now):
+				return fmt.Errorf("a timer cannot start in the future")
 			}
-			if n > 0 {
-				return fmt.Errorf("an active timer already exists (use --allow-multiple to override)")
+			e.Text = c.Text
+			e.Tags = db.ParseTags(db.JoinTags(append(e.Tags, c.Tags...)))
+			if c.Project != "" && !cmd.Flags().Changed("project") {
+				e.Project = c.Project
 			}
+			if c.At != nil {
+				e.TS = *c.At
+			}
+		}
+		if strings.TrimSpace(e.Text) == "" {
+			return fmt.Errorf("timer text is empty once inline tokens are removed (use --raw to keep them)")
 		}
 
-		text := strings.Join(args, " ")
-		// Ensure "active" tag is present only once
-		tags := strings.Trim(strings.ReplaceAll(startTags+",active", ",,", ","), ", ")
-		res, err := dbh.Exec(`INSERT INTO entries(category, text, project, tags) VALUES('timer', ?, ?, ?)`, text, startProject, tags)
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		e, err = st.StartTimer(e, allowMulti)
+		if errors.Is(err, store.ErrTimerRunning) {
+			return fmt.Errorf("an active timer already exists (pulse pause it, or use --allow-multiple to override)")
+		}
 		if err != nil {
 			return err
 		}
-		id, _ := res.LastInsertId()
-		fmt.Printf("Timer #%d started at %s\n", id, time.Now().Format(time.Kitchen))
+		fmt.Printf("Timer #%d started at %s\n", e.ID, e.StartedAt.In(cfg.Location()).Format(time.Kitchen))
+		warnProject(st, e.Project)
 		return nil
 	},
 }
 
 func init() {
 	startCmd.Flags().StringVarP(&startProject, "project", "p", "", "Project name")
-	startCmd.Flags().StringVarP(&startTags, "tags", "t", "", "Additional comma separated tags")
+	startCmd.Flags().StringVarP(&startTags, "tags", "t", "", "Comma separated tags")
+	startCmd.Flags().BoolVar(&startRaw, "raw", false, "Store the text as typed, without parsing #tag @project ^time")
+	startCmd.Flags().BoolVar(&startNoBill, "non-billable", false, "Keep the tracked time off invoices")
 	startCmd.Flags().BoolVar(&allowMulti, "allow-multiple", false, "Allow multiple concurrent active timers")
 }
@@ -1,61 +1,90 @@
 package cmd
 
 import (
-	"database/sql"
 	"fmt"
 	"time"
 
-	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
 	"github.com/ramanasai/pulse/internal/ui"
 	"github.com/spf13/cobra"
 )
 
-// summaryCmd prints a per-category breakdown for today and totals.
+var (
+	summarySince string
+	summaryUntil string
+)
+
+// summaryCmd prints a per-category breakdown for today (or --since/--until) and totals.
 var summaryCmd = &cobra.Command{
 	Use:   "summary",
 	Short: "Daily summary",
+	Long: `Examples:
+	pulse summary                      # today
+	pulse summary --since yesterday --until yesterday
+	pulse summary --since "this week"`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbh, err := db.Open()
+		start, end, err := parseRange(summarySince, summaryUntil, timeparse.Day(time.Now().In(cfg.Location()), cfg.DayStart()))
 		if err != nil {
 			return err
 		}
-		defer dbh.Close()
-
-		start := time.Now().Truncate(24 * time.Hour)
-		rows, err := dbh.Query(`
-			SELECT category, COUNT(*), COALESCE(SUM(duration_minutes),0)
-			FROM entries
-			WHERE ts >= ?
-			GROUP BY category
-			ORDER BY category ASC
-		`, start.Format(time.RFC3339))
+
+		st, err := openStore()
 		if err != nil {
 			return err
 		}
-		defer rows.Close()
-
-		// fmt.Printf("Today (%s):\n", start.Format("2006-01-02"))
-		fmt.Println(ui.DefaultTheme.Title.Render("Today"), ui.DefaultTheme.Value.Render(start.Format("2006-01-02")))
-		var totalCount int64
-		var totalMins int64
-		for rows.Next() {
-			var cat string
-			var n, mins sql.NullInt64
-			if err := rows.Scan(&cat, &n, &mins); err != nil {
-				return err
-			}
-			// fmt.Printf("  %-10s %3d items, %4d mins\n", cat, n.Int64, mins.Int64)
-			line := fmt.Sprintf("  %-10s %3d items, %4d mins", cat, n.Int64, mins.Int64)
-			fmt.Println(ui.DefaultTheme.Value.Render(line))
-			totalCount += n.Int64
-			totalMins += mins.Int64
+		defer st.Close()
+
+		totals, err := st.Summarize(store.Filter{Since: start, Until: end})
+		if err != nil {
+			return err
 		}
-		if err := rows.Err(); err != nil {
+		if done, err := writeTotals(totals); done || err != nil {
 			return err
 		}
-		// fmt.Printf("  %-10s %3d items, %4d mins\n", "TOTAL", totalCount, totalMins)
-		total := fmt.Sprintf("  %-10s %3d items, %4d mins", "TOTAL", totalCount, totalMins)
+
+		if summarySince == "" && summaryUntil == "" {
+			fmt.Println(ui.DefaultTheme.Title.Render("Today"), ui.DefaultTheme.Value.Render(start.Format("2006-01-02")))
+		} else {
+			period := start.Format("2006-01-02 15:04") + " → "
+			if end.IsZero() {
+				period += "now"
+			} else {
+				period += end.Format("2006-01-02 15:04")
+			}
+			fmt.Println(ui.DefaultTheme.Title.Render("Summary"), ui.DefaultTheme.Value.Render(period))
+		}
+		var totalCount int
+		var totalDur time.Duration
+		for _, t := range totals {
+			line := fmt.Sprintf("  %-10s %3d items, %8s", t.Category, t.Count, formatDuration(t.Duration))
+			fmt.Println(ui.DefaultTheme.Value.Render(line))
+			totalCount += t.Count
+			totalDur += t.Duration
+		}
+		total := fmt.Sprintf("  %-10s %3d items, %8s", "TOTAL", totalCount, formatDuration(totalDur))
 		fmt.Println(ui.DefaultTheme.Success.Render(total))
 		return nil
 	},
 }
+
+func init() {
+	summaryCmd.Flags().StringVar(&summarySince, "since", "", `Start: today, yesterday, 7d, "this week", 2025-09-01… (default: today)`)
+	summaryCmd.Flags().StringVar(&summaryUntil, "until", "", "End, same forms as --since; a bare day includes that day (default: now)")
+}
+
+// formatDuration renders d as "2h05m", "12m30s" or "45s".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	h := int(d / time.Hour)
+	m := int(d % time.Hour / time.Minute)
+	s := int(d % time.Minute / time.Second)
+	switch {
+	case h > 0:
+		return fmt.Sprintf("%dh%02dm", h, m)
+	case m > 0:
+		return fmt.Sprintf("%dm%02ds", m, s)
+	default:
+		return fmt.Sprintf("%ds", s)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// tagsCmd lists the tag vocabulary; its subcommands clean it up.
+var tagsCmd = &cobra.Command{
+	Use:     "tags",
+	Aliases: []string{"tag"},
+	Short:   "List tags with usage counts; rename, merge or delete them",
+	Long: `Without a subcommand, lists every tag on entries outside the trash with
+how many entries carry it and when it was last used.
+
+rename, merge and delete rewrite every affected entry (trashed ones too) in
+one transaction; pulse undo reverts them.
+
+Examples:
+	pulse tags
+	pulse tags rename kube k8s
+	pulse tags merge kubernetes K8S k8s
+	pulse tags delete wip`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		tags, err := st.Tags()
+		if err != nil {
+			return err
+		}
+		loc := cfg.Location()
+		switch {
+		case outFormat.Machine():
+			rows := make([]output.Tag, len(tags))
+			for i, t := range tags {
+				rows[i] = output.Tag{Name: t.Name, Entries: t.Entries, LastUsed: t.LastUsed.UTC(), Rate: t.Rate}
+			}
+			return output.Write(os.Stdout, outFormat, rows)
+		case outFormat == output.Plain:
+			for _, t := range tags {
+				fmt.Printf("%s\t%d\t%s\n", t.Name, t.Entries, t.LastUsed.In(loc).Format("2006-01-02"))
+			}
+			return nil
+		}
+
+		if len(tags) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("no tags yet"))
+			return nil
+		}
+		for _, t := range tags {
+			line := ui.DefaultTheme.Value.Render(fmt.Sprintf("  #%-24s %5d %s", t.Name, t.Entries, plural(t.Entries, "entry  ", "entries"))) +
+				ui.DefaultTheme.Hint.Render("  last "+t.LastUsed.In(loc).Format("2006-01-02"))
+			if t.Rate > 0 {
+				line += ui.DefaultTheme.Hint.Render(fmt.Sprintf("  rate %g/h", t.Rate))
+			}
+			fmt.Println(line)
+		}
+		return nil
+	},
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every entry",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		n, err := st.RenameTag(args[0], args[1])
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Renamed #%s to #%s on %d %s.\n", strings.TrimPrefix(args[0], "#"), strings.TrimPrefix(args[1], "#"), n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+var tagsMergeCmd = &cobra.Command{
+	Use:   "merge <from...> <into>",
+	Short: "Replace one or more tags with another on every entry",
+	Args:  cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		from, into := args[:len(args)-1], args[len(args)-1]
+		n, err := st.MergeTags(from, into)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Merged %s into #%s on %d %s.\n", hashTags(from), strings.TrimPrefix(into, "#"), n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+var tagsDeleteCmd = &cobra.Command{
+	Use:   "delete <tag...>",
+	Short: "Remove tags from every entry",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		n, err := st.DeleteTags(args)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Removed %s from %d %s.\n", hashTags(args), n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+var tagsRateCmd = &cobra.Command{
+	Use:   "rate <tag> <rate>",
+	Short: "Bill entries with a tag at their own hourly rate (0 to clear)",
+	Long: `A tag rate overrides the project's rate on invoices, e.g. for urgent or
+weekend work. When an entry has several rated tags, the highest rate wins.`,
+	Example: `  pulse tags rate urgent 180`,
+	Args:    cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rate, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || rate < 0 {
+			return fmt.Errorf("invalid rate %q", args[1])
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if err := st.SetTagRate(args[0], rate); err != nil {
+			return err
+		}
+		if rate == 0 {
+			fmt.Printf("Cleared the rate of %s.\n", hashTags(args[:1]))
+		} else {
+			fmt.Printf("%s now bills at %g per hour.\n", hashTags(args[:1]), rate)
+		}
+		return nil
+	},
+}
+
+func init() {
+	tagsCmd.AddCommand(tagsRenameCmd, tagsMergeCmd, tagsDeleteCmd, tagsRateCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
+
+// hashTags renders names as "#a, #b".
+func hashTags(names []string) string {
+	out := make([]string, len(names))
+	for i, n := range names {
+		out[i] = "#" + strings.TrimPrefix(n, "#")
+	}
+	return strings.Join(out, ", ")
+}
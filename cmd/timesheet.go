@@ -0,0 +1,243 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/output"
+	"github.com/ramanasai/pulse/internal/report"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/timeparse"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	timesheetWeek     string
+	timesheetRounding string
+)
+
+// timesheetCmd shows a week of tracked time as a project × day grid.
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "Weekly grid of tracked time: projects × workdays",
+	Long: `A week of tracked time with projects as rows and days as columns, plus
+daily and weekly totals. Columns follow reminder.workdays; other days only
+appear when something was tracked on them. Notes without a timer are not
+counted. With a rounding policy (--rounding or rounding in the config) each
+cell is rounded and the unrounded total is shown too.
+
+--week takes an ISO week (2025-W40), "last", or any day in the week
+(yesterday, 2025-09-30…). The default is the current week.
+
+Examples:
+	pulse timesheet
+	pulse timesheet --week last
+	pulse timesheet --week 2025-W40 -o csv > timesheet.csv`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		start, err := parseWeek(timesheetWeek)
+		if err != nil {
+			return err
+		}
+		r, err := roundingPolicy(cmd, timesheetRounding, cfg.RoundingPolicy())
+		if err != nil {
+			return err
+		}
+
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		entries, err := st.ListEntries(store.Filter{
+			Since: start,
+			Until: start.AddDate(0, 0, 7),
+			Limit: store.NoLimit,
+		})
+		if err != nil {
+			return err
+		}
+		sheet := report.Timesheet(entries, start, cfg.Reminder.Workdays, cfg.DayStart(), r, time.Now())
+
+		switch outFormat {
+		case output.JSON:
+			return writeTimesheetJSON(sheet, r)
+		case output.NDJSON:
+			return writeTimesheetCells(sheet)
+		case output.CSV, output.Plain:
+			return writeTimesheetGrid(sheet)
+		}
+
+		last := sheet.Start.AddDate(0, 0, 6)
+		fmt.Println(ui.DefaultTheme.Title.Render("Timesheet"), ui.DefaultTheme.Value.Render(sheet.Week()),
+			ui.DefaultTheme.Hint.Render(sheet.Start.Format("Jan 2")+" – "+last.Format("Jan 2, 2006")))
+		cell := func(d time.Duration) string {
+			if d == 0 {
+				return "·"
+			}
+			return report.FormatHM(d)
+		}
+		header := fmt.Sprintf("  %-24s", "Project")
+		for _, d := range sheet.Days {
+			header += fmt.Sprintf(" %7s", d.Format("Mon 02"))
+		}
+		fmt.Println(ui.DefaultTheme.Label.Render(header + fmt.Sprintf(" %8s", "Total")))
+		for _, r := range sheet.Rows {
+			line := fmt.Sprintf("  %-24s", r.Project)
+			for _, d := range r.Days {
+				line += fmt.Sprintf(" %7s", cell(d))
+			}
+			fmt.Println(ui.DefaultTheme.Value.Render(line + fmt.Sprintf(" %8s", report.FormatHM(r.Total))))
+		}
+		if len(sheet.Rows) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("  nothing tracked this week"))
+		}
+		total := fmt.Sprintf("  %-24s", "TOTAL")
+		for _, d := range sheet.Daily {
+			total += fmt.Sprintf(" %7s", cell(d))
+		}
+		fmt.Println(ui.DefaultTheme.Success.Render(total + fmt.Sprintf(" %8s", report.FormatHM(sheet.Total))))
+		if !r.IsZero() {
+			fmt.Println(ui.DefaultTheme.Hint.Render(fmt.Sprintf("  rounded %s · tracked %s", r, report.FormatHM(sheet.Tracked))))
+		}
+		return nil
+	},
+}
+
+func init() {
+	timesheetCmd.Flags().StringVar(&timesheetWeek, "week", "", `ISO week (2025-W40), "last", or a day in the week (default: this week)`)
+	timesheetCmd.Flags().StringVar(&timesheetRounding, "rounding", "", roundingUsage)
+	rootCmd.AddCommand(timesheetCmd)
+}
+
+// parseWeek resolves --week to the start of its Monday in the configured
+// timezone, honouring day_starts_at.
+func parseWeek(s string) (time.Time, error) {
+	loc, dayStart := cfg.Location(), cfg.DayStart()
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch s {
+	case "", "this":
+		s = "this week"
+	case "last":
+		s = "last week"
+	}
+	if strings.Contains(s, "w") && s[0] >= '0' && s[0] <= '9' {
+		monday, err := report.ParseWeek(s, loc)
+		if err != nil {
+			return monday, fmt.Errorf("--week: %w", err)
+		}
+		return timeparse.At(monday, dayStart), nil
+	}
+	since, err := timeparse.Since(s, time.Now().In(loc), dayStart)
+	if err != nil {
+		return since, fmt.Errorf("--week: %w", err)
+	}
+	day := timeparse.Day(since, dayStart)
+	return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7)), nil
+}
+
+type timesheetRowJSON struct {
+	Project        string  `json:"project"`
+	Days           []int64 `json:"days"`
+	Seconds        int64   `json:"seconds"`
+	Duration       string  `json:"duration"`
+	TrackedSeconds int64   `json:"tracked_seconds"` // before rounding
+}
+
+func seconds(ds []time.Duration) []int64 {
+	out := make([]int64, len(ds))
+	for i, d := range ds {
+		out[i] = int64(d / time.Second)
+	}
+	return out
+}
+
+// writeTimesheetJSON writes the grid as one object; "days" in each row line
+// up with the top-level "days" dates.
+func writeTimesheetJSON(s *report.Sheet, r rounding.Policy) error {
+	days := make([]string, len(s.Days))
+	for i, d := range s.Days {
+		days[i] = d.Format("2006-01-02")
+	}
+	rows := make([]timesheetRowJSON, len(s.Rows))
+	for i, row := range s.Rows {
+		rows[i] = timesheetRowJSON{row.Project, seconds(row.Days), int64(row.Total / time.Second), report.FormatHM(row.Total), int64(row.Tracked / time.Second)}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(map[string]any{
+		"week":     s.Week(),
+		"days":     days,
+		"rounding": r.String(),
+		"projects": rows,
+		"total":    timesheetRowJSON{"TOTAL", seconds(s.Daily), int64(s.Total / time.Second), report.FormatHM(s.Total), int64(s.Tracked / time.Second)},
+	})
+}
+
+// writeTimesheetCells writes one NDJSON object per non-empty project/day cell.
+func writeTimesheetCells(s *report.Sheet) error {
+	enc := json.NewEncoder(os.Stdout)
+	for _, r := range s.Rows {
+		for i, d := range r.Days {
+			if d == 0 {
+				continue
+			}
+			if err := enc.Encode(map[string]any{
+				"week":     s.Week(),
+				"project":  r.Project,
+				"day":      s.Days[i].Format("2006-01-02"),
+				"seconds":  int64(d / time.Second),
+				"duration": report.FormatHM(d),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeTimesheetGrid writes the grid as CSV (decimal hours, for spreadsheets)
+// or tab-separated hours:minutes, with a TOTAL row last and the time tracked
+// before rounding as the last column.
+func writeTimesheetGrid(s *report.Sheet) error {
+	format := report.FormatHM
+	if outFormat == output.CSV {
+		format = func(d time.Duration) string {
+			return strconv.FormatFloat(d.Round(time.Minute).Hours(), 'f', 2, 64)
+		}
+	}
+	header := []string{"project"}
+	for _, d := range s.Days {
+		header = append(header, d.Format("2006-01-02"))
+	}
+	records := [][]string{append(header, "total", "tracked")}
+	row := func(name string, days []time.Duration, total, tracked time.Duration) {
+		rec := []string{name}
+		for _, d := range days {
+			rec = append(rec, format(d))
+		}
+		records = append(records, append(rec, format(total), format(tracked)))
+	}
+	for _, r := range s.Rows {
+		row(r.Project, r.Days, r.Total, r.Tracked)
+	}
+	row("TOTAL", s.Daily, s.Total, s.Tracked)
+
+	if outFormat == output.Plain {
+		for _, rec := range records[1:] {
+			fmt.Println(strings.Join(rec, "\t"))
+		}
+		return nil
+	}
+	w := csv.NewWriter(os.Stdout)
+	w.WriteAll(records)
+	return w.Error()
+}
@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var purgeYes bool
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "List, restore or purge deleted entries",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Show entries in the trash",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		entries, err := st.ListEntries(store.Filter{Trashed: true, Limit: store.NoLimit})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			fmt.Println(ui.DefaultTheme.Hint.Render("trash is empty"))
+			return nil
+		}
+		loc := cfg.Location()
+		for _, e := range entries {
+			text := strings.ReplaceAll(strings.TrimSpace(e.Text), "\n", " ")
+			if r := []rune(text); len(r) > 60 {
+				text = string(r[:59]) + "…"
+			}
+			line := ui.DefaultTheme.Label.Render(fmt.Sprintf("[%d] deleted %s", e.ID, e.DeletedAt.In(loc).Format("2006-01-02 15:04"))) +
+				"  " + ui.DefaultTheme.Value.Render(e.Category)
+			if e.Project != "" {
+				line += "  [" + e.Project + "]"
+			}
+			fmt.Println(line + "  " + text)
+		}
+		return nil
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore <id...>",
+	Short: "Restore entries from the trash",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if err := st.RestoreEntries(ids); err != nil {
+			return err
+		}
+		fmt.Printf("Restored %d %s.\n", len(ids), plural(len(ids), "entry", "entries"))
+		return nil
+	},
+}
+
+var trashPurgeCmd = &cobra.Command{
+	Use:   "purge [id...]",
+	Short: "Permanently delete trashed entries (all when no ids are given)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ids, err := parseIDs(args)
+		if err != nil {
+			return err
+		}
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		if !purgeYes {
+			what := "all entries in the trash"
+			if len(ids) > 0 {
+				what = fmt.Sprintf("%d %s", len(ids), plural(len(ids), "entry", "entries"))
+			}
+			fmt.Printf("Permanently delete %s? This cannot be undone. [y/N] ", what)
+			answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+			if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+				fmt.Println("Aborted.")
+				return nil
+			}
+		}
+
+		n, err := st.PurgeEntries(ids)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Purged %d %s.\n", n, plural(n, "entry", "entries"))
+		return nil
+	},
+}
+
+func init() {
+	trashPurgeCmd.Flags().BoolVarP(&purgeYes, "yes", "y", false, "Do not ask for confirmation")
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashPurgeCmd)
+	rootCmd.AddCommand(trashCmd)
+}
@@ -1,10 +1,9 @@
 package cmd
 
 import (
-	"database/sql"
-	"github.com/spf13/cobra"
-	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
 	"github.com/ramanasai/pulse/internal/ui"
+	"github.com/spf13/cobra"
 )
 
 // tuiCmd launches the Bubble Tea TUI.
@@ -12,35 +11,16 @@ var tuiCmd = &cobra.Command{
 	Use:   "tui",
 	Short: "Open TUI",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		dbh, err := db.Open()
+		st, err := openStore()
 		if err != nil {
 			return err
 		}
-		defer dbh.Close()
+		defer st.Close()
 
-		rows, err := dbh.Query(`
-			SELECT '['||substr(ts,12,5)||'] '||COALESCE(project,'')||
-			       CASE WHEN project IS NULL OR project='' THEN '' ELSE ' ' END || text
-			FROM entries
-			ORDER BY ts DESC
-			LIMIT 200
-		`)
+		entries, err := st.ListEntries(store.Filter{Limit: 200})
 		if err != nil {
 			return err
 		}
-		defer rows.Close()
-
-		var list []string
-		for rows.Next() {
-			var s sql.NullString
-			if err := rows.Scan(&s); err != nil {
-				return err
-			}
-			list = append(list, s.String)
-		}
-		if err := rows.Err(); err != nil {
-			return err
-		}
-		return ui.Run(list)
+		return ui.Run(entries, cfg.Location(), loadProjectColors(st))
 	},
 }
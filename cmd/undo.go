@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ramanasai/pulse/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// undoCmd reverts the most recent mutating command using the operations journal.
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Revert the last change (log, start, stop, pause, resume, edit, rm, restore)",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		st, err := openStore()
+		if err != nil {
+			return err
+		}
+		defer st.Close()
+
+		op, err := st.Undo()
+		if errors.Is(err, store.ErrNothingToUndo) {
+			fmt.Println("Nothing to undo.")
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ids := make([]string, 0, len(op.EntryIDs)+len(op.Projects)+len(op.Tags))
+		for _, id := range op.EntryIDs {
+			ids = append(ids, fmt.Sprintf("#%d", id))
+		}
+		for _, p := range op.Projects {
+			ids = append(ids, "project "+p)
+		}
+		for _, t := range op.Tags {
+			ids = append(ids, "tag #"+t)
+		}
+		fmt.Printf("Undid %s of %s (from %s).\n", op.Kind, strings.Join(ids, ", "), op.TS.In(cfg.Location()).Format("2006-01-02 15:04"))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+}
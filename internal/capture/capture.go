@@ -0,0 +1,104 @@
+// Package capture parses the inline quick-capture syntax accepted by
+// pulse log and pulse start:
+//
+//	#tag  @project  +category  ~30m  ^yesterday_15:00
+//
+// Recognized tokens are removed from the text. Anything that does not parse
+// (e.g. "#123", which usually references an issue, or "~/src") is left in
+// place, and a leading backslash keeps a token literal: \#notatag.
+package capture
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Result is the text with its inline tokens extracted. Zero values mean the
+// token was absent; when a token repeats, the last one wins (tags accumulate).
+type Result struct {
+	Text     string
+	Tags     []string
+	Project  string
+	Category string
+	Duration time.Duration
+	At       *time.Time
+}
+
+// Parse extracts inline tokens from text. Times after ^ are read by
+// timeparse.Instant relative to now, with "_" standing in for spaces.
+func Parse(text string, now time.Time) Result {
+	var r Result
+	var kept []string
+	for _, word := range strings.Fields(text) {
+		if rest, ok := strings.CutPrefix(word, `\`); ok && len(rest) > 0 && strings.ContainsRune("#@+~^", rune(rest[0])) {
+			kept = append(kept, rest)
+			continue
+		}
+		if !r.token(word, now) {
+			kept = append(kept, word)
+		}
+	}
+	r.Text = strings.Join(kept, " ")
+	return r
+}
+
+// token applies word to r if it is a recognized token.
+func (r *Result) token(word string, now time.Time) bool {
+	if len(word) < 2 {
+		return false
+	}
+	val := word[1:]
+	switch word[0] {
+	case '#':
+		name := trimName(val)
+		if name == "" || isDigits(name) {
+			return false
+		}
+		r.Tags = append(r.Tags, name)
+	case '@':
+		name := trimName(val)
+		if name == "" {
+			return false
+		}
+		r.Project = name
+	case '+':
+		name := trimName(val)
+		if first, _ := utf8.DecodeRuneInString(name); !unicode.IsLetter(first) {
+			return false
+		}
+		r.Category = strings.ToLower(name)
+	case '~':
+		d, err := time.ParseDuration(val)
+		if err != nil || d <= 0 {
+			return false
+		}
+		r.Duration = d
+	case '^':
+		t, err := timeparse.Instant(strings.ReplaceAll(val, "_", " "), now)
+		if err != nil {
+			return false
+		}
+		r.At = &t
+	default:
+		return false
+	}
+	return true
+}
+
+// trimName drops trailing punctuation so "fix #bug, then" tags "bug".
+func trimName(s string) string {
+	return strings.TrimRight(s, ".,;:!?)")
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,32 @@
+package capture
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.Local)
+	yesterday := time.Date(2025, 10, 14, 15, 0, 0, 0, time.Local)
+	tests := []struct {
+		in   string
+		want Result
+	}{
+		{"plain note", Result{Text: "plain note"}},
+		{"fix #bug @acme +Task ~30m", Result{Text: "fix", Tags: []string{"bug"}, Project: "acme", Category: "task", Duration: 30 * time.Minute}},
+		{"review #123 with #ops", Result{Text: "review #123 with", Tags: []string{"ops"}}},
+		{"cd ~/src and ~0m", Result{Text: "cd ~/src and ~0m"}},
+		{`\#literal \@home \~5m`, Result{Text: "#literal @home ~5m"}},
+		{"fix #bug, then (see @acme).", Result{Text: "fix then (see", Tags: []string{"bug"}, Project: "acme"}},
+		{"@one @two work @three", Result{Text: "work", Project: "three"}},
+		{"#a #b #a", Result{Tags: []string{"a", "b", "a"}}},
+		{"call ^yesterday_15:00", Result{Text: "call", At: &yesterday}},
+		{"^never +1 # @ +", Result{Text: "^never +1 # @ +"}},
+	}
+	for _, tt := range tests {
+		if got := Parse(tt.in, now); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/ramanasai/pulse/internal/rounding"
 	"github.com/spf13/viper"
 )
 
@@ -18,9 +19,46 @@ type ReminderConfig struct {
 	Timezone string   `mapstructure:"timezone"`  // e.g. "Asia/Kolkata" (optional)
 }
 
+type BackupConfig struct {
+	Auto       bool   `mapstructure:"auto"`        // snapshot the database once a day
+	Dir        string `mapstructure:"dir"`         // default: backups/<db name> next to the database
+	KeepDaily  int    `mapstructure:"keep_daily"`  // daily snapshots to keep
+	KeepWeekly int    `mapstructure:"keep_weekly"` // weekly snapshots to keep
+}
+
+type InvoiceConfig struct {
+	Prefix   string `mapstructure:"prefix"`   // invoice numbers are prefix + a running number, e.g. INV-0007
+	Currency string `mapstructure:"currency"` // for projects without their own
+	Rounding string `mapstructure:"rounding"` // like the top-level rounding; "" uses it
+	From     string `mapstructure:"from"`     // your name and address, printed at the top
+}
+
+type GoalsConfig struct {
+	Weekly  string `mapstructure:"weekly"`   // "40h": time to track each week across all projects; "" for none
+	AlertAt []int  `mapstructure:"alert_at"` // percentages of a budget or goal that notify after pulse stop
+}
+
 type Config struct {
 	Theme    string         `mapstructure:"theme"`
+	DB       string         `mapstructure:"db"` // optional database path; "~/" is expanded
 	Reminder ReminderConfig `mapstructure:"reminder"`
+	Backup   BackupConfig   `mapstructure:"backup"`
+	Invoice  InvoiceConfig  `mapstructure:"invoice"`
+	Goals    GoalsConfig    `mapstructure:"goals"`
+
+	// DayStartsAt ("04:00") moves the boundary between days, so work after
+	// midnight counts toward the previous day in summaries and reports.
+	DayStartsAt string `mapstructure:"day_starts_at"`
+
+	// Rounding ("15m up", "6m nearest per day") rounds tracked time in
+	// report, timesheet and export; "" keeps time as tracked.
+	Rounding string `mapstructure:"rounding"`
+
+	// InlineSyntax enables #tag @project +category ~30m ^time parsing in log/start text.
+	InlineSyntax bool `mapstructure:"inline_syntax"`
+
+	// Profile is the name of the profile overlaid by LoadProfile ("" for none).
+	Profile string `mapstructure:"-"`
 }
 
 func Default() Config {
@@ -33,6 +71,19 @@ func Default() Config {
 			Holidays: []string{},
 			Timezone: "",
 		},
+		Backup: BackupConfig{
+			Auto:       false,
+			KeepDaily:  7,
+			KeepWeekly: 4,
+		},
+		Invoice: InvoiceConfig{
+			Prefix:   "INV-",
+			Currency: "USD",
+		},
+		Goals: GoalsConfig{
+			AlertAt: []int{80, 100},
+		},
+		InlineSyntax: true,
 	}
 }
 
@@ -44,8 +95,31 @@ func xdgConfigPath() (string, error) {
 	return filepath.Join(dir, "config.yaml"), nil
 }
 
+// DataDir is $XDG_DATA_HOME/pulse, falling back to ~/.local/share/pulse.
+func DataDir() (string, error) {
+	base := os.Getenv("XDG_DATA_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(base, "pulse"), nil
+}
+
+// Load reads the config, applying the profile named by $PULSE_PROFILE if set.
 func Load() (Config, error) {
+	return LoadProfile(os.Getenv("PULSE_PROFILE"))
+}
+
+// LoadProfile reads config.yaml and, when name is non-empty, overlays the
+// settings under profiles.<name> on top of it. Profile names are
+// case-insensitive; cfg.Profile holds the lowercased name, so every spelling
+// opens the same database.
+func LoadProfile(name string) (Config, error) {
 	cfg := Default()
+	cfg.Profile = strings.ToLower(strings.TrimSpace(name))
 
 	path, err := xdgConfigPath()
 	if err != nil {
@@ -63,8 +137,34 @@ func Load() (Config, error) {
 	v.SetDefault("reminder.workdays", cfg.Reminder.Workdays)
 	v.SetDefault("reminder.holidays", cfg.Reminder.Holidays)
 	v.SetDefault("reminder.timezone", cfg.Reminder.Timezone)
+	v.SetDefault("backup.auto", cfg.Backup.Auto)
+	v.SetDefault("backup.dir", cfg.Backup.Dir)
+	v.SetDefault("backup.keep_daily", cfg.Backup.KeepDaily)
+	v.SetDefault("backup.keep_weekly", cfg.Backup.KeepWeekly)
+	v.SetDefault("invoice.prefix", cfg.Invoice.Prefix)
+	v.SetDefault("invoice.currency", cfg.Invoice.Currency)
+	v.SetDefault("invoice.rounding", cfg.Invoice.Rounding)
+	v.SetDefault("invoice.from", cfg.Invoice.From)
+	v.SetDefault("goals.weekly", cfg.Goals.Weekly)
+	v.SetDefault("goals.alert_at", cfg.Goals.AlertAt)
+	v.SetDefault("rounding", cfg.Rounding)
+	v.SetDefault("inline_syntax", cfg.InlineSyntax)
+	v.SetDefault("day_starts_at", cfg.DayStartsAt)
 
 	_ = v.ReadInConfig() // ok if missing
+	if cfg.Profile != "" {
+		key := "profiles." + cfg.Profile
+		if !v.IsSet(key) {
+			return cfg, fmt.Errorf("unknown profile %q (define it under profiles: in %s)", name, path)
+		}
+		if err := v.MergeConfigMap(v.GetStringMap(key)); err != nil {
+			return cfg, fmt.Errorf("profile %s: %w", name, err)
+		}
+		// Profiles never share the base database unless they say so
+		if !v.IsSet(key + ".db") {
+			v.Set("db", "")
+		}
+	}
 	if err := v.Unmarshal(&cfg); err != nil {
 		return cfg, fmt.Errorf("config unmarshal: %w", err)
 	}
@@ -73,9 +173,115 @@ func Load() (Config, error) {
 	for i, d := range cfg.Reminder.Workdays {
 		cfg.Reminder.Workdays[i] = strings.Title(strings.ToLower(strings.TrimSpace(d[:3])))
 	}
+	if _, err := parseDayStart(cfg.DayStartsAt); err != nil {
+		return cfg, err
+	}
+	if _, err := parseGoal(cfg.Goals.Weekly); err != nil {
+		return cfg, err
+	}
+	if _, err := rounding.Parse(cfg.Rounding); err != nil {
+		return cfg, err
+	}
+	if _, err := rounding.Parse(cfg.Invoice.Rounding); err != nil {
+		return cfg, fmt.Errorf("invoice: %w", err)
+	}
 	return cfg, nil
 }
 
+// DatabasePath is the db setting if present, otherwise pulse.db (or
+// pulse-<profile>.db for a named profile) inside DataDir.
+func (c Config) DatabasePath() (string, error) {
+	if p := strings.TrimSpace(c.DB); p != "" {
+		return expandHome(p)
+	}
+	dir, err := DataDir()
+	if err != nil {
+		return "", err
+	}
+	if c.Profile != "" {
+		return filepath.Join(dir, "pulse-"+c.Profile+".db"), nil
+	}
+	return filepath.Join(dir, "pulse.db"), nil
+}
+
+// BackupDir is the <name> directory for the database at dbPath inside the
+// backup.dir setting if present, otherwise inside a backups directory next to
+// the database. Each database rotates its own snapshots.
+func (c Config) BackupDir(dbPath string) (string, error) {
+	name := strings.TrimSuffix(filepath.Base(dbPath), filepath.Ext(dbPath))
+	if d := strings.TrimSpace(c.Backup.Dir); d != "" {
+		dir, err := expandHome(d)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, name), nil
+	}
+	return filepath.Join(filepath.Dir(dbPath), "backups", name), nil
+}
+
+func expandHome(p string) (string, error) {
+	rest, ok := strings.CutPrefix(p, "~/")
+	if !ok {
+		return p, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// DayStart is day_starts_at as an offset from midnight; 0 when unset or invalid.
+func (c Config) DayStart() time.Duration {
+	d, _ := parseDayStart(c.DayStartsAt)
+	return d
+}
+
+func parseDayStart(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil || t.Hour() >= 12 {
+		return 0, fmt.Errorf("day_starts_at %q: want HH:MM before 12:00, e.g. \"04:00\"", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// WeeklyGoal is goals.weekly; 0 when unset or invalid.
+func (c Config) WeeklyGoal() time.Duration {
+	d, _ := parseGoal(c.Goals.Weekly)
+	return d
+}
+
+func parseGoal(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("goals.weekly %q: want a duration, e.g. \"40h\"", s)
+	}
+	return d, nil
+}
+
+// RoundingPolicy is the rounding setting; none when unset or invalid.
+func (c Config) RoundingPolicy() rounding.Policy {
+	p, _ := rounding.Parse(c.Rounding)
+	return p
+}
+
+// InvoiceRounding is invoice.rounding, falling back to rounding.
+func (c Config) InvoiceRounding() rounding.Policy {
+	if strings.TrimSpace(c.Invoice.Rounding) == "" {
+		return c.RoundingPolicy()
+	}
+	p, _ := rounding.Parse(c.Invoice.Rounding)
+	return p
+}
+
 func (c Config) Location() *time.Location {
 	if tz := strings.TrimSpace(c.Reminder.Timezone); tz != "" {
 		if loc, err := time.LoadLocation(tz); err == nil {
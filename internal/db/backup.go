@@ -0,0 +1,158 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Backup writes a consistent snapshot of db to dest using VACUUM INTO, which
+// is safe while other connections are writing. dest must not exist yet.
+func Backup(db *sql.DB, dest string) error {
+	if _, err := os.Stat(dest); err == nil {
+		return fmt.Errorf("backup %s already exists", dest)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return err
+	}
+	if _, err := db.Exec(`VACUUM INTO ?`, dest); err != nil {
+		return fmt.Errorf("backup to %s: %w", dest, err)
+	}
+	return nil
+}
+
+// ValidateBackup checks that path is an intact pulse database whose schema
+// this build understands, and returns its schema version.
+func ValidateBackup(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		return 0, err
+	}
+	dsn, err := fileURI(path, "mode=ro")
+	if err != nil {
+		return 0, err
+	}
+	dbh, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return 0, err
+	}
+	defer dbh.Close()
+
+	var check string
+	if err := dbh.QueryRow(`PRAGMA integrity_check`).Scan(&check); err != nil {
+		return 0, fmt.Errorf("%s is not a readable SQLite database: %w", path, err)
+	}
+	if check != "ok" {
+		return 0, fmt.Errorf("%s failed integrity check: %s", path, check)
+	}
+	var n int
+	if err := dbh.QueryRow(`SELECT count(*) FROM sqlite_master WHERE type='table' AND name IN ('schema_version','entries')`).Scan(&n); err != nil {
+		return 0, err
+	}
+	if n != 2 {
+		return 0, fmt.Errorf("%s is not a pulse database (no schema_version/entries tables)", path)
+	}
+	var v int
+	if err := dbh.QueryRow(`SELECT COALESCE(MAX(version),0) FROM schema_version`).Scan(&v); err != nil {
+		return 0, err
+	}
+	if latest := LatestVersion(); v < 1 || v > latest {
+		return v, fmt.Errorf("%s has schema version %d; this build supports 1..%d", path, v, latest)
+	}
+	return v, nil
+}
+
+// ReplaceDatabase swaps the database file at dst for a copy of src. Callers
+// must have closed every connection to dst. The copy is renamed into place so
+// dst is never left half written, and stale WAL files are removed.
+func ReplaceDatabase(src, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp := dst + ".restore-tmp"
+	if err := copyFile(src, tmp); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dst + suffix); err != nil && !os.IsNotExist(err) {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, dst)
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// RotateBackups keeps one snapshot per day (daily-YYYY-MM-DD.db) and per ISO
+// week (weekly-YYYY-Www.db) in dir, creating today's and this week's if they
+// are missing and deleting all but the newest keepDaily/keepWeekly of each.
+// It returns the paths it created.
+func RotateBackups(db *sql.DB, dir string, keepDaily, keepWeekly int, now time.Time) ([]string, error) {
+	year, week := now.ISOWeek()
+	kinds := []struct {
+		prefix, name string
+		keep         int
+	}{
+		{"daily-", "daily-" + now.Format("2006-01-02") + ".db", keepDaily},
+		{"weekly-", fmt.Sprintf("weekly-%04d-W%02d.db", year, week), keepWeekly},
+	}
+
+	var created []string
+	for _, k := range kinds {
+		if k.keep <= 0 {
+			continue
+		}
+		path := filepath.Join(dir, k.name)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			if err := Backup(db, path); err != nil {
+				return created, err
+			}
+			created = append(created, path)
+		}
+		if err := prune(dir, k.prefix, k.keep); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+// prune deletes all but the newest keep files in dir named prefix*.db; the
+// names embed the date so lexical order is chronological.
+func prune(dir, prefix string, keep int) error {
+	matches, err := filepath.Glob(filepath.Join(dir, prefix+"*.db"))
+	if err != nil {
+		return err
+	}
+	sort.Strings(matches)
+	for len(matches) > keep {
+		if err := os.Remove(matches[0]); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		matches = matches[1:]
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupPaths(t *testing.T) {
+	// ? and # end the file name in a URI unless escaped, and %20 must not be decoded
+	dir := filepath.Join(t.TempDir(), "a?b#c %20d")
+	path := filepath.Join(dir, "pulse.db")
+	db, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("database not created at %s: %v", path, err)
+	}
+
+	backup := filepath.Join(dir, "backup?1#.db")
+	if err := Backup(db, backup); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := ValidateBackup(backup); err != nil || v != LatestVersion() {
+		t.Errorf("ValidateBackup = %d, %v; want %d", v, err, LatestVersion())
+	}
+
+	notDB := filepath.Join(dir, "notes?.txt")
+	if err := os.WriteFile(notDB, []byte("not a database"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateBackup(notDB); err == nil {
+		t.Error("ValidateBackup accepted a text file")
+	}
+}
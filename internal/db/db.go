@@ -2,54 +2,52 @@ package db
 
 import (
 	"database/sql"
-	"embed"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 
 	_ "modernc.org/sqlite"
 )
 
-//go:embed schema.sql
-var schemaFS embed.FS
-
-func appDataDir() (string, error) {
-	home, err := os.UserHomeDir()
+// Open connects to the database at path and applies any pending migrations.
+func Open(path string) (*sql.DB, error) {
+	db, err := Connect(path)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	base := filepath.Join(home, ".local", "share", "pulse")
-	if err := os.MkdirAll(base, 0o755); err != nil {
-		return "", err
+	if _, err := Migrate(db); err != nil {
+		db.Close()
+		return nil, errors.Join(fmt.Errorf("schema migration failed"), err)
 	}
-	return base, nil
+	return db, nil
 }
 
-func Open() (*sql.DB, error) {
-	dir, err := appDataDir()
-	if err != nil {
+// Connect opens the database at path, creating its directory if needed,
+// without touching its schema.
+func Connect(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		return nil, err
 	}
-	path := filepath.Join(dir, "pulse.db")
-	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout=5000&_pragma=foreign_keys=ON", path)
-	db, err := sql.Open("sqlite", dsn)
+	dsn, err := fileURI(path, "_pragma=busy_timeout=5000&_pragma=foreign_keys=ON&_pragma=journal_mode=WAL")
 	if err != nil {
 		return nil, err
 	}
-	if err := migrate(db); err != nil {
-		return nil, err
-	}
-	return db, nil
+	return sql.Open("sqlite", dsn)
 }
 
-func migrate(db *sql.DB) error {
-	b, err := schemaFS.ReadFile("schema.sql")
+// fileURI turns path into a file: URI with the given query, escaping
+// characters such as ? and # that would otherwise end the file name.
+func fileURI(path, query string) (string, error) {
+	abs, err := filepath.Abs(path)
 	if err != nil {
-		return err
+		return "", err
 	}
-	if _, err := db.Exec(string(b)); err != nil {
-		return errors.Join(fmt.Errorf("schema apply failed"), err)
+	p := filepath.ToSlash(abs)
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p // C:/... on Windows
 	}
-	return nil
+	return (&url.URL{Scheme: "file", Path: p, RawQuery: query}).String(), nil
 }
@@ -0,0 +1,165 @@
+package db
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+func connect(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := Connect(filepath.Join(t.TempDir(), "data", "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestMigrations(t *testing.T) {
+	ms, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, m := range ms {
+		if m.Version != i+1 {
+			t.Errorf("migration %d is %04d_%s; versions must be 1, 2, 3, ... without gaps", i, m.Version, m.Name)
+		}
+		if m.Name == "" || m.SQL == "" {
+			t.Errorf("migration %04d has no name or SQL", m.Version)
+		}
+	}
+	if got := LatestVersion(); got != len(ms) {
+		t.Errorf("LatestVersion = %d, want %d", got, len(ms))
+	}
+}
+
+func TestMigrateFresh(t *testing.T) {
+	db := connect(t)
+	applied, err := Migrate(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != LatestVersion() {
+		t.Errorf("applied %d migrations, want %d", len(applied), LatestVersion())
+	}
+	if v, err := SchemaVersion(db); err != nil || v != LatestVersion() {
+		t.Errorf("SchemaVersion = %d, %v", v, err)
+	}
+
+	again, err := Migrate(db)
+	if err != nil || len(again) != 0 {
+		t.Errorf("second Migrate applied %d, %v", len(again), err)
+	}
+	status, err := Status(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range status {
+		if !s.Applied || s.AppliedAt == "" {
+			t.Errorf("migration %04d_%s not reported applied", s.Version, s.Name)
+		}
+	}
+}
+
+// TestMigrateUnversioned adopts a database created before schema_version
+// existed as version 1.
+func TestMigrateUnversioned(t *testing.T) {
+	db := connect(t)
+	ms, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(ms[0].SQL); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO entries(ts, text) VALUES('2025-10-01T09:00:00.000Z', 'kept')`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	var text string
+	if err := db.QueryRow(`SELECT text FROM entries`).Scan(&text); err != nil || text != "kept" {
+		t.Errorf("entry after migrating = %q, %v", text, err)
+	}
+}
+
+func TestMigrateNewerSchema(t *testing.T) {
+	db := connect(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec(`INSERT INTO schema_version(version, name) VALUES(?, 'future')`, LatestVersion()+1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Migrate(db); err == nil {
+		t.Error("Migrate accepted a schema newer than this build")
+	}
+}
+
+// TestMigrateLegacy upgrades a version 1 database, whose tags were only the
+// comma separated entries.tags column and whose timers were tracked with
+// duration_minutes and the "active" pseudo-tag.
+func TestMigrateLegacy(t *testing.T) {
+	db := connect(t)
+	ms, err := Migrations()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SchemaVersion(db); err != nil {
+		t.Fatal(err)
+	}
+	if err := apply(db, ms[0]); err != nil {
+		t.Fatal(err)
+	}
+	legacy := []struct {
+		category, text, tags string
+		minutes              int
+	}{
+		{"note", "untagged", "", 0},
+		{"note", "tagged", "a, b,a", 0},
+		{"timer", "stopped timer", "work", 45},
+		{"timer", "running timer", "work,active", 0},
+	}
+	for _, e := range legacy {
+		if _, err := db.Exec(`INSERT INTO entries(ts, category, text, tags, duration_minutes) VALUES('2025-10-01T09:00:00.000Z', ?, ?, ?, ?)`,
+			e.category, e.text, e.tags, e.minutes); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	applied, err := Migrate(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(ms)-1 {
+		t.Errorf("applied %d migrations, want %d", len(applied), len(ms)-1)
+	}
+
+	tests := []struct {
+		text    string
+		tags    string
+		started sql.NullString
+		ended   sql.NullString
+	}{
+		{"untagged", "", sql.NullString{}, sql.NullString{}},
+		{"tagged", "a,b", sql.NullString{}, sql.NullString{}},
+		{"stopped timer", "work", valid("2025-10-01T09:00:00.000Z"), valid("2025-10-01T09:45:00.000Z")},
+		{"running timer", "work", valid("2025-10-01T09:00:00.000Z"), sql.NullString{}},
+	}
+	for _, tt := range tests {
+		var tags string
+		var started, ended sql.NullString
+		err := db.QueryRow(`SELECT COALESCE((SELECT group_concat(t.name, ',') FROM (SELECT t.name FROM entry_tags et JOIN tags t ON t.id = et.tag_id WHERE et.entry_id = e.id ORDER BY t.name) t), ''),
+			started_at, ended_at FROM entries e WHERE text = ?`, tt.text).Scan(&tags, &started, &ended)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.text, err)
+		}
+		if tags != tt.tags || started != tt.started || ended != tt.ended {
+			t.Errorf("%s: tags %q, started %v, ended %v; want %q, %v, %v", tt.text, tags, started, ended, tt.tags, tt.started, tt.ended)
+		}
+	}
+}
+
+func valid(s string) sql.NullString { return sql.NullString{String: s, Valid: true} }
@@ -1,6 +1,5 @@
-PRAGMA journal_mode=WAL;
-PRAGMA foreign_keys=ON;
-
+-- Initial schema. Statements use IF NOT EXISTS so databases created before
+-- versioned migrations existed are adopted as version 1 without changes.
 
 CREATE TABLE IF NOT EXISTS entries (
 id INTEGER PRIMARY KEY,
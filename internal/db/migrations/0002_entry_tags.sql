@@ -0,0 +1,44 @@
+-- Normalized tag storage. entry_tags is authoritative for filtering;
+-- entries.tags is kept as a comma joined copy so entries_fts keeps indexing it.
+
+CREATE TABLE tags (
+id INTEGER PRIMARY KEY,
+name TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE entry_tags (
+entry_id INTEGER NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+tag_id INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+PRIMARY KEY (entry_id, tag_id)
+);
+
+CREATE INDEX idx_entry_tags_tag ON entry_tags(tag_id);
+
+
+-- Backfill from the old comma separated column
+CREATE TEMP TABLE split_tags AS
+WITH RECURSIVE split(entry_id, tag, rest) AS (
+SELECT id, '', COALESCE(tags,'') || ',' FROM entries
+UNION ALL
+SELECT entry_id,
+trim(substr(rest, 1, instr(rest, ',') - 1)),
+substr(rest, instr(rest, ',') + 1)
+FROM split WHERE rest <> ''
+)
+SELECT DISTINCT entry_id, tag FROM split WHERE tag <> '';
+
+INSERT OR IGNORE INTO tags(name) SELECT DISTINCT tag FROM split_tags;
+
+INSERT OR IGNORE INTO entry_tags(entry_id, tag_id)
+SELECT s.entry_id, t.id FROM split_tags s JOIN tags t ON t.name = s.tag;
+
+DROP TABLE split_tags;
+
+
+-- Rewrite the denormalized column in canonical form (trimmed, no duplicates)
+UPDATE entries SET tags = (
+SELECT group_concat(name, ',') FROM (
+SELECT t.name FROM entry_tags et JOIN tags t ON t.id = et.tag_id
+WHERE et.entry_id = entries.id ORDER BY t.id
+)
+);
@@ -0,0 +1,40 @@
+-- Timers get explicit bounds. A running timer is a row with started_at set
+-- and ended_at NULL; durations are always ended_at - started_at, so the
+-- "active" pseudo-tag and the truncated duration_minutes column go away.
+
+ALTER TABLE entries ADD COLUMN started_at TEXT;
+ALTER TABLE entries ADD COLUMN ended_at TEXT;
+
+
+-- Running timers were marked with the "active" tag
+UPDATE entries SET started_at = ts
+WHERE category = 'timer' AND id IN (
+SELECT et.entry_id FROM entry_tags et JOIN tags t ON t.id = et.tag_id WHERE t.name = 'active'
+);
+
+-- Stopped timers (and anything else with a recorded duration) end at ts + duration
+UPDATE entries SET
+started_at = ts,
+ended_at = strftime('%Y-%m-%dT%H:%M:%fZ', ts, '+' || COALESCE(duration_minutes, 0) || ' minutes')
+WHERE started_at IS NULL AND (category = 'timer' OR COALESCE(duration_minutes, 0) > 0);
+
+
+-- Drop the pseudo-tag from timers and rebuild their denormalized tag column
+DELETE FROM entry_tags
+WHERE tag_id IN (SELECT id FROM tags WHERE name = 'active')
+AND entry_id IN (SELECT id FROM entries WHERE category = 'timer');
+
+DELETE FROM tags WHERE name = 'active' AND id NOT IN (SELECT tag_id FROM entry_tags);
+
+UPDATE entries SET tags = (
+SELECT group_concat(name, ',') FROM (
+SELECT t.name FROM entry_tags et JOIN tags t ON t.id = et.tag_id
+WHERE et.entry_id = entries.id ORDER BY t.id
+)
+)
+WHERE category = 'timer';
+
+
+ALTER TABLE entries DROP COLUMN duration_minutes;
+
+CREATE INDEX idx_entries_running ON entries(started_at) WHERE ended_at IS NULL;
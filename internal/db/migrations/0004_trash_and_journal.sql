@@ -0,0 +1,24 @@
+-- Soft delete: entries with deleted_at set are in the trash and hidden from
+-- list, search, summary and the TUI until restored or purged.
+ALTER TABLE entries ADD COLUMN deleted_at TEXT;
+
+CREATE INDEX idx_entries_deleted ON entries(deleted_at);
+
+
+-- Operations journal used by `pulse undo`. Each mutating command records one
+-- operation plus, per touched entry, a JSON snapshot of the entry before the
+-- change (NULL when the operation created it).
+CREATE TABLE operations (
+id INTEGER PRIMARY KEY,
+ts TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+kind TEXT NOT NULL,
+undone_at TEXT
+);
+
+CREATE TABLE operation_entries (
+operation_id INTEGER NOT NULL REFERENCES operations(id) ON DELETE CASCADE,
+entry_id INTEGER NOT NULL,
+before TEXT
+);
+
+CREATE INDEX idx_operation_entries_op ON operation_entries(operation_id);
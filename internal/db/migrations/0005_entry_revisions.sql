@@ -0,0 +1,30 @@
+-- Audit trail: every UPDATE that changes an entry stores the previous values.
+CREATE TABLE entry_revisions (
+id INTEGER PRIMARY KEY,
+entry_id INTEGER NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+changed_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+ts TEXT NOT NULL,
+category TEXT NOT NULL,
+text TEXT NOT NULL,
+project TEXT,
+tags TEXT,
+started_at TEXT,
+ended_at TEXT,
+deleted_at TEXT
+);
+
+CREATE INDEX idx_entry_revisions_entry ON entry_revisions(entry_id, id);
+
+CREATE TRIGGER entries_revision AFTER UPDATE ON entries
+WHEN old.ts IS NOT new.ts
+OR old.category IS NOT new.category
+OR old.text IS NOT new.text
+OR old.project IS NOT new.project
+OR old.tags IS NOT new.tags
+OR old.started_at IS NOT new.started_at
+OR old.ended_at IS NOT new.ended_at
+OR old.deleted_at IS NOT new.deleted_at
+BEGIN
+INSERT INTO entry_revisions(entry_id, ts, category, text, project, tags, started_at, ended_at, deleted_at)
+VALUES (old.id, old.ts, old.category, old.text, old.project, old.tags, old.started_at, old.ended_at, old.deleted_at);
+END;
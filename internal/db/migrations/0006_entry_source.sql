@@ -0,0 +1,5 @@
+-- Imported entries remember a fingerprint of the record they came from so
+-- importing the same file twice does not duplicate them.
+ALTER TABLE entries ADD COLUMN source TEXT;
+
+CREATE UNIQUE INDEX idx_entries_source ON entries(source) WHERE source IS NOT NULL;
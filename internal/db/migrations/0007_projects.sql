@@ -0,0 +1,14 @@
+-- Registered projects. Entries still store the project by name; this table
+-- adds metadata and lets pulse warn about names that are not registered.
+CREATE TABLE projects (
+id INTEGER PRIMARY KEY,
+name TEXT NOT NULL UNIQUE,
+client TEXT,
+color TEXT,
+archived_at TEXT,
+created_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now'))
+);
+
+-- Every project already in use is registered
+INSERT INTO projects(name)
+SELECT DISTINCT project FROM entries WHERE project IS NOT NULL AND project <> '';
@@ -0,0 +1,37 @@
+-- Billing: hourly rates on projects (and optionally tags), a billable flag on
+-- entries, and the invoices issued so far for running invoice numbers.
+ALTER TABLE projects ADD COLUMN rate REAL;
+ALTER TABLE projects ADD COLUMN currency TEXT;
+ALTER TABLE tags ADD COLUMN rate REAL;
+
+ALTER TABLE entries ADD COLUMN billable INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE entry_revisions ADD COLUMN billable INTEGER;
+
+CREATE TABLE invoices (
+id INTEGER PRIMARY KEY,
+number TEXT NOT NULL UNIQUE,
+project TEXT NOT NULL,
+period_start TEXT NOT NULL,
+period_end TEXT NOT NULL,
+total REAL NOT NULL DEFAULT 0,
+currency TEXT NOT NULL DEFAULT '',
+issued_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+UNIQUE (project, period_start, period_end)
+);
+
+DROP TRIGGER entries_revision;
+
+CREATE TRIGGER entries_revision AFTER UPDATE ON entries
+WHEN old.ts IS NOT new.ts
+OR old.category IS NOT new.category
+OR old.text IS NOT new.text
+OR old.project IS NOT new.project
+OR old.tags IS NOT new.tags
+OR old.started_at IS NOT new.started_at
+OR old.ended_at IS NOT new.ended_at
+OR old.deleted_at IS NOT new.deleted_at
+OR old.billable IS NOT new.billable
+BEGIN
+INSERT INTO entry_revisions(entry_id, ts, category, text, project, tags, started_at, ended_at, deleted_at, billable)
+VALUES (old.id, old.ts, old.category, old.text, old.project, old.tags, old.started_at, old.ended_at, old.deleted_at, old.billable);
+END;
@@ -0,0 +1,4 @@
+-- Time budgets on projects: a total, and one per week or month, in seconds.
+ALTER TABLE projects ADD COLUMN budget INTEGER;
+ALTER TABLE projects ADD COLUMN period_budget INTEGER;
+ALTER TABLE projects ADD COLUMN budget_period TEXT;
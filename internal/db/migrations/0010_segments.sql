@@ -0,0 +1,35 @@
+-- Work segments of paused and resumed timers. A timer that was never paused
+-- has none and runs from started_at to ended_at; once paused, its tracked
+-- time is the sum of its segments, and the segment without ended_at is the
+-- one running now.
+CREATE TABLE segments (
+id INTEGER PRIMARY KEY,
+entry_id INTEGER NOT NULL REFERENCES entries(id) ON DELETE CASCADE,
+started_at TEXT NOT NULL,
+ended_at TEXT
+);
+
+CREATE INDEX segments_entry ON segments(entry_id, started_at);
+
+-- Revisions keep the segments as they were, packed like the store reads them
+-- ("start/end,start/end…"), so history shows what a paused timer had tracked.
+ALTER TABLE entry_revisions ADD COLUMN segments TEXT;
+
+DROP TRIGGER entries_revision;
+
+CREATE TRIGGER entries_revision AFTER UPDATE ON entries
+WHEN old.ts IS NOT new.ts
+OR old.category IS NOT new.category
+OR old.text IS NOT new.text
+OR old.project IS NOT new.project
+OR old.tags IS NOT new.tags
+OR old.started_at IS NOT new.started_at
+OR old.ended_at IS NOT new.ended_at
+OR old.deleted_at IS NOT new.deleted_at
+OR old.billable IS NOT new.billable
+BEGIN
+INSERT INTO entry_revisions(entry_id, ts, category, text, project, tags, started_at, ended_at, deleted_at, billable, segments)
+VALUES (old.id, old.ts, old.category, old.text, old.project, old.tags, old.started_at, old.ended_at, old.deleted_at, old.billable,
+(SELECT group_concat(sg.started_at || '/' || COALESCE(sg.ended_at,''), ',')
+FROM (SELECT started_at, ended_at FROM segments WHERE entry_id = old.id ORDER BY started_at) sg));
+END;
@@ -0,0 +1,10 @@
+-- Registry rows touched by an operation (project rename and merge), so undo
+-- puts the projects table back along with the entries. before is a JSON
+-- snapshot of the row, NULL when the operation created it.
+CREATE TABLE operation_projects (
+operation_id INTEGER NOT NULL REFERENCES operations(id) ON DELETE CASCADE,
+project_id INTEGER NOT NULL,
+before TEXT
+);
+
+CREATE INDEX idx_operation_projects_op ON operation_projects(operation_id);
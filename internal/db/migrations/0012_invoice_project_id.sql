@@ -0,0 +1,24 @@
+-- Invoices belong to their project by id, so a renamed project keeps its
+-- invoice numbers; project is the name the invoice was last issued under.
+-- SQLite cannot drop the old UNIQUE (project, ...) constraint, so the table
+-- is rebuilt.
+CREATE TABLE invoices_new (
+id INTEGER PRIMARY KEY,
+number TEXT NOT NULL UNIQUE,
+project_id INTEGER,
+project TEXT NOT NULL,
+period_start TEXT NOT NULL,
+period_end TEXT NOT NULL,
+total REAL NOT NULL DEFAULT 0,
+currency TEXT NOT NULL DEFAULT '',
+issued_at TEXT NOT NULL DEFAULT (strftime('%Y-%m-%dT%H:%M:%fZ','now')),
+UNIQUE (project_id, period_start, period_end)
+);
+
+INSERT INTO invoices_new(id, number, project_id, project, period_start, period_end, total, currency, issued_at)
+SELECT i.id, i.number, (SELECT p.id FROM projects p WHERE p.name = i.project), i.project,
+i.period_start, i.period_end, i.total, i.currency, i.issued_at
+FROM invoices i;
+
+DROP TABLE invoices;
+ALTER TABLE invoices_new RENAME TO invoices;
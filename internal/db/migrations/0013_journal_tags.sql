@@ -0,0 +1,10 @@
+-- Tag rows touched by an operation (tags rename, merge and delete), so undo
+-- brings back their rates along with the entries. before is a JSON snapshot
+-- of the row, NULL when the operation created it.
+CREATE TABLE operation_tags (
+operation_id INTEGER NOT NULL REFERENCES operations(id) ON DELETE CASCADE,
+name TEXT NOT NULL,
+before TEXT
+);
+
+CREATE INDEX idx_operation_tags_op ON operation_tags(operation_id);
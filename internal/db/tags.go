@@ -0,0 +1,74 @@
+package db
+
+import (
+	"database/sql"
+	"strings"
+)
+
+// Execer is satisfied by both *sql.DB and *sql.Tx.
+type Execer interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// ParseTags splits a comma separated tag list, trimming blanks and a leading
+// '#' and dropping empty and duplicate names while keeping the given order.
+func ParseTags(s string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "#")
+		if t == "" || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	return out
+}
+
+// JoinTags is the inverse of ParseTags and the form stored in entries.tags.
+func JoinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+// SetEntryTags replaces the tags of an entry in entry_tags and keeps the
+// denormalized entries.tags column (indexed by entries_fts) in sync.
+func SetEntryTags(ex Execer, entryID int64, tags []string) error {
+	if _, err := ex.Exec(`DELETE FROM entry_tags WHERE entry_id=?`, entryID); err != nil {
+		return err
+	}
+	for _, t := range tags {
+		if _, err := ex.Exec(`INSERT OR IGNORE INTO tags(name) VALUES(?)`, t); err != nil {
+			return err
+		}
+		if _, err := ex.Exec(`INSERT OR IGNORE INTO entry_tags(entry_id, tag_id) SELECT ?, id FROM tags WHERE name=?`, entryID, t); err != nil {
+			return err
+		}
+	}
+	joined := JoinTags(tags)
+	_, err := ex.Exec(`UPDATE entries SET tags=NULLIF(?,'') WHERE id=? AND COALESCE(tags,'') <> ?`, joined, entryID, joined)
+	return err
+}
+
+// TagCondition returns an SQL condition (and its args) matching entries whose
+// id column is idCol and that carry all (or, with matchAny, at least one) of tags
+// exactly. It returns "" when tags is empty.
+func TagCondition(idCol string, tags []string, matchAny bool) (string, []any) {
+	if len(tags) == 0 {
+		return "", nil
+	}
+	args := make([]any, 0, len(tags)+1)
+	for _, t := range tags {
+		args = append(args, t)
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(tags)), ",")
+	sub := `SELECT COUNT(DISTINCT et.tag_id) FROM entry_tags et JOIN tags t ON t.id = et.tag_id
+				WHERE et.entry_id = ` + idCol + ` AND t.name IN (` + in + `)`
+	if matchAny {
+		return "(" + sub + ") > 0", args
+	}
+	args = append(args, len(tags))
+	return "(" + sub + ") = ?", args
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"bug", []string{"bug"}},
+		{" #bug , prod,,bug, #", []string{"bug", "prod"}},
+		{"b,a", []string{"b", "a"}},
+	}
+	for _, tt := range tests {
+		got := ParseTags(tt.in)
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("ParseTags(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+		if JoinTags(got) != JoinTags(tt.want) {
+			t.Errorf("JoinTags(%q) = %q", got, JoinTags(got))
+		}
+	}
+}
+
+func TestTagCondition(t *testing.T) {
+	db := connect(t)
+	if _, err := Migrate(db); err != nil {
+		t.Fatal(err)
+	}
+
+	entries := map[string][]string{
+		"none":     nil,
+		"bug":      {"bug"},
+		"prod":     {"prod"},
+		"bug+prod": {"bug", "prod"},
+		"bugfix":   {"bugfix"},
+	}
+	for text, tags := range entries {
+		res, err := db.Exec(`INSERT INTO entries(category, text) VALUES('note', ?)`, text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		id, _ := res.LastInsertId()
+		if err := SetEntryTags(db, id, tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		tags     []string
+		matchAny bool
+		want     []string
+	}{
+		{nil, false, []string{"bug", "bug+prod", "bugfix", "none", "prod"}},
+		{[]string{"bug"}, false, []string{"bug", "bug+prod"}},
+		{[]string{"bug", "prod"}, false, []string{"bug+prod"}},
+		{[]string{"bug", "prod"}, true, []string{"bug", "bug+prod", "prod"}},
+		{[]string{"missing"}, true, nil},
+		{[]string{"bug", "missing"}, false, nil},
+	}
+	for _, tt := range tests {
+		q := `SELECT text FROM entries e`
+		cond, args := TagCondition("e.id", tt.tags, tt.matchAny)
+		if cond != "" {
+			q += ` WHERE ` + cond
+		}
+		rows, err := db.Query(q+` ORDER BY text`, args...)
+		if err != nil {
+			t.Fatalf("%v: %v", tt.tags, err)
+		}
+		var got []string
+		for rows.Next() {
+			var s string
+			if err := rows.Scan(&s); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, s)
+		}
+		rows.Close()
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("TagCondition(%v, any=%v) matched %q, want %q", tt.tags, tt.matchAny, got, tt.want)
+		}
+	}
+
+	// entries.tags follows entry_tags for full-text search
+	var tags string
+	if err := db.QueryRow(`SELECT tags FROM entries WHERE text = 'bug+prod'`).Scan(&tags); err != nil || tags != "bug,prod" {
+		t.Errorf("entries.tags = %q, %v", tags, err)
+	}
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeLayout is how ts, started_at and ended_at are stored: UTC with
+// millisecond precision, matching strftime('%Y-%m-%dT%H:%M:%fZ') so values
+// written from Go and from SQL defaults sort and compare as plain text.
+const TimeLayout = "2006-01-02T15:04:05.000Z"
+
+// DurationSQL yields the tracked seconds of the entry aliased e. Running
+// timers count up to now, timers with segments their sum (see
+// model.Entry.Duration); entries without a start count zero.
+const DurationSQL = `CAST(ROUND(COALESCE(
+	(SELECT SUM(julianday(COALESCE(sg.ended_at, e.ended_at, 'now')) - julianday(sg.started_at)) FROM segments sg WHERE sg.entry_id = e.id),
+	julianday(COALESCE(e.ended_at, 'now')) - julianday(e.started_at), 0) * 86400) AS INTEGER)`
+
+// FormatTime renders t in TimeLayout.
+func FormatTime(t time.Time) string {
+	return t.UTC().Format(TimeLayout)
+}
+
+// ParseTime parses a stored timestamp, accepting TimeLayout and RFC3339 variants.
+func ParseTime(s string) (time.Time, error) {
+	for _, layout := range []string{TimeLayout, time.RFC3339Nano, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized timestamp %q", s)
+}
@@ -0,0 +1,302 @@
+// Package doctor checks a pulse database for corruption and drift and
+// optionally repairs what it can.
+package doctor
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+)
+
+// Options controls which repairs Run performs.
+type Options struct {
+	Fix      bool          // apply repairs instead of only reporting
+	MaxTimer time.Duration // timers running longer than this are flagged
+	Now      time.Time
+}
+
+// Finding is one problem found by a check.
+type Finding struct {
+	Check   string
+	Problem string
+	Fixed   bool
+	Action  string // what --fix did (or would do)
+}
+
+// Report is the outcome of Run, in check order.
+type Report struct {
+	Checks   []string
+	Findings []Finding
+}
+
+type check struct {
+	name string
+	run  func(dbh *sql.DB, o Options) ([]Finding, error)
+}
+
+var checks = []check{
+	{"integrity", checkIntegrity},
+	{"fts", checkFTS},
+	{"timestamps", checkTimestamps},
+	{"tags", checkTags},
+	{"timers", checkTimers},
+}
+
+// Run executes every check in order.
+func Run(dbh *sql.DB, o Options) (Report, error) {
+	if o.Now.IsZero() {
+		o.Now = time.Now()
+	}
+	var r Report
+	for _, c := range checks {
+		fs, err := c.run(dbh, o)
+		if err != nil {
+			return r, fmt.Errorf("%s check: %w", c.name, err)
+		}
+		r.Checks = append(r.Checks, c.name)
+		r.Findings = append(r.Findings, fs...)
+	}
+	return r, nil
+}
+
+func checkIntegrity(dbh *sql.DB, o Options) ([]Finding, error) {
+	rows, err := dbh.Query(`PRAGMA integrity_check`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var out []Finding
+	for rows.Next() {
+		var msg string
+		if err := rows.Scan(&msg); err != nil {
+			return nil, err
+		}
+		if msg != "ok" {
+			out = append(out, Finding{Check: "integrity", Problem: msg, Action: "restore from a backup (pulse restore)"})
+		}
+	}
+	return out, rows.Err()
+}
+
+func checkFTS(dbh *sql.DB, o Options) ([]Finding, error) {
+	// rank=1 makes FTS5 compare the index against the entries content table
+	_, err := dbh.Exec(`INSERT INTO entries_fts(entries_fts, rank) VALUES('integrity-check', 1)`)
+	if err == nil {
+		return nil, nil
+	}
+	f := Finding{Check: "fts", Problem: "search index is out of sync with entries: " + err.Error(), Action: "rebuild entries_fts"}
+	if o.Fix {
+		if _, err := dbh.Exec(`INSERT INTO entries_fts(entries_fts) VALUES('rebuild')`); err != nil {
+			return nil, err
+		}
+		f.Fixed = true
+	}
+	return []Finding{f}, nil
+}
+
+// checkTimestamps flags time columns that are not in db.TimeLayout. Values
+// db.ParseTime understands are rewritten in canonical form; others are only reported.
+func checkTimestamps(dbh *sql.DB, o Options) ([]Finding, error) {
+	var out []Finding
+	for _, col := range []string{"ts", "started_at", "ended_at", "deleted_at"} {
+		// CAST keeps the driver from parsing DATETIME columns into time.Time
+		rows, err := dbh.Query(`SELECT id, CAST(` + col + ` AS TEXT) FROM entries WHERE ` + col + ` IS NOT NULL`)
+		if err != nil {
+			return nil, err
+		}
+		type bad struct {
+			id  int64
+			val string
+		}
+		var bads []bad
+		for rows.Next() {
+			var b bad
+			if err := rows.Scan(&b.id, &b.val); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			if t, err := time.Parse(db.TimeLayout, b.val); err != nil || db.FormatTime(t) != b.val {
+				bads = append(bads, b)
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		for _, b := range bads {
+			f := Finding{Check: "timestamps", Problem: fmt.Sprintf("entry #%d: %s %q is not a canonical UTC timestamp", b.id, col, b.val)}
+			t, err := db.ParseTime(b.val)
+			if err != nil {
+				f.Action = "cannot be parsed; fix it with pulse edit"
+				out = append(out, f)
+				continue
+			}
+			f.Action = "rewrite as " + db.FormatTime(t)
+			if o.Fix {
+				if _, err := dbh.Exec(`UPDATE entries SET `+col+`=? WHERE id=?`, db.FormatTime(t), b.id); err != nil {
+					return nil, err
+				}
+				f.Fixed = true
+			}
+			out = append(out, f)
+		}
+	}
+	return out, nil
+}
+
+// checkTags compares entries.tags with entry_tags. entry_tags is
+// authoritative, except that tags only present in the column (e.g. written by
+// an older pulse) are adopted into it.
+func checkTags(dbh *sql.DB, o Options) ([]Finding, error) {
+	rows, err := dbh.Query(`
+		SELECT e.id, COALESCE(e.tags,''),
+			COALESCE((SELECT group_concat(t.name, char(31)) FROM entry_tags et JOIN tags t ON t.id = et.tag_id WHERE et.entry_id = e.id), '')
+		FROM entries e`)
+	if err != nil {
+		return nil, err
+	}
+	type bad struct {
+		id       int64
+		col      string
+		relation []string
+	}
+	var bads []bad
+	for rows.Next() {
+		var b bad
+		var rel string
+		if err := rows.Scan(&b.id, &b.col, &rel); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if rel != "" {
+			b.relation = strings.Split(rel, "\x1f")
+		}
+		parsed := db.ParseTags(b.col)
+		canonical := db.JoinTags(parsed) == b.col
+		if !canonical || !sameSet(parsed, b.relation) {
+			bads = append(bads, b)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []Finding
+	for _, b := range bads {
+		want := b.relation
+		if len(want) == 0 {
+			want = db.ParseTags(b.col)
+		}
+		f := Finding{
+			Check:   "tags",
+			Problem: fmt.Sprintf("entry #%d: tags %q do not match its tag links [%s]", b.id, b.col, strings.Join(b.relation, ",")),
+			Action:  fmt.Sprintf("set tags to %q", db.JoinTags(want)),
+		}
+		if o.Fix {
+			tx, err := dbh.Begin()
+			if err != nil {
+				return nil, err
+			}
+			if err := db.SetEntryTags(tx, b.id, want); err != nil {
+				tx.Rollback()
+				return nil, err
+			}
+			if err := tx.Commit(); err != nil {
+				return nil, err
+			}
+			f.Fixed = true
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		if !slices.Contains(b, x) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkTimers flags timers that have been running longer than MaxTimer,
+// typically left behind by a crashed stop. --fix ends them at start+MaxTimer.
+// A resumed timer counts from its last resume; a paused one is never stale.
+func checkTimers(dbh *sql.DB, o Options) ([]Finding, error) {
+	if o.MaxTimer <= 0 {
+		return nil, nil
+	}
+	rows, err := dbh.Query(`
+		SELECT e.id, e.started_at, (SELECT max(sg.started_at) FROM segments sg WHERE sg.entry_id = e.id AND sg.ended_at IS NULL)
+		FROM entries e
+		WHERE e.started_at IS NOT NULL AND e.ended_at IS NULL AND e.deleted_at IS NULL
+			AND (NOT EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id)
+				OR EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id AND sg.ended_at IS NULL))`)
+	if err != nil {
+		return nil, err
+	}
+	type stale struct {
+		id      int64
+		start   time.Time
+		resumed bool
+	}
+	var stales []stale
+	for rows.Next() {
+		var id int64
+		var s string
+		var resumed sql.NullString
+		if err := rows.Scan(&id, &s, &resumed); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if resumed.Valid {
+			s = resumed.String
+		}
+		start, err := db.ParseTime(s)
+		if err != nil {
+			continue // reported by the timestamps check
+		}
+		if o.Now.Sub(start) > o.MaxTimer {
+			stales = append(stales, stale{id, start, resumed.Valid})
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var out []Finding
+	for _, s := range stales {
+		end := s.start.Add(o.MaxTimer)
+		since := "start"
+		if s.resumed {
+			since = "resume"
+		}
+		f := Finding{
+			Check:   "timers",
+			Problem: fmt.Sprintf("timer #%d has been running for %s", s.id, o.Now.Sub(s.start).Round(time.Minute)),
+			Action:  fmt.Sprintf("stop it at %s (%s + %s); adjust with pulse edit", db.FormatTime(end), since, o.MaxTimer),
+		}
+		if o.Fix {
+			if _, err := dbh.Exec(`UPDATE entries SET ended_at=? WHERE id=?`, db.FormatTime(end), s.id); err != nil {
+				return nil, err
+			}
+			if _, err := dbh.Exec(`UPDATE segments SET ended_at=? WHERE entry_id=? AND ended_at IS NULL`, db.FormatTime(end), s.id); err != nil {
+				return nil, err
+			}
+			f.Fixed = true
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
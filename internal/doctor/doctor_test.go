@@ -0,0 +1,175 @@
+package doctor
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+)
+
+var now = time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+
+func at(d time.Duration) string { return db.FormatTime(now.Add(-d)) }
+
+// seed inserts an entry and returns its id; start and end may be "".
+func seed(t *testing.T, dbh *sql.DB, text, start, end string, tags ...string) int64 {
+	t.Helper()
+	ts := start
+	if ts == "" {
+		ts = at(time.Hour)
+	}
+	res, err := dbh.Exec(`INSERT INTO entries(ts, category, text, started_at, ended_at) VALUES(?, 'timer', ?, NULLIF(?,''), NULLIF(?,''))`, ts, text, start, end)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := res.LastInsertId()
+	if err := db.SetEntryTags(dbh, id, tags); err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+func exec(t *testing.T, dbh *sql.DB, q string, args ...any) {
+	t.Helper()
+	if _, err := dbh.Exec(q, args...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRun(t *testing.T) {
+	dbh, err := db.Open(filepath.Join(t.TempDir(), "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbh.Close()
+
+	ok := seed(t, dbh, "fine", at(2*time.Hour), at(time.Hour), "a")
+
+	// A search index row without an entry
+	exec(t, dbh, `INSERT INTO entries_fts(rowid, text, tags) VALUES(999, 'ghost', '')`)
+
+	// One timestamp in local layout, one that cannot be parsed
+	local := seed(t, dbh, "local ts", "", "")
+	exec(t, dbh, `UPDATE entries SET ts='2025-10-01 09:00:00' WHERE id=?`, local)
+	garbled := seed(t, dbh, "garbled end", at(3*time.Hour), at(2*time.Hour))
+	exec(t, dbh, `UPDATE entries SET ended_at='soon' WHERE id=?`, garbled)
+
+	// Tags only in the column are adopted; otherwise the links win
+	columnOnly := seed(t, dbh, "column only", "", "")
+	exec(t, dbh, `UPDATE entries SET tags='x, y' WHERE id=?`, columnOnly)
+	drifted := seed(t, dbh, "drifted", "", "", "a", "b")
+	exec(t, dbh, `UPDATE entries SET tags='a' WHERE id=?`, drifted)
+
+	// Timers: forgotten for 30h, running for 1h, paused 30h ago, resumed 20h
+	// ago after starting 30h ago, and resumed 1h ago
+	forgotten := seed(t, dbh, "forgotten", at(30*time.Hour), "")
+	seed(t, dbh, "running", at(time.Hour), "")
+	paused := seed(t, dbh, "paused", at(30*time.Hour), "")
+	exec(t, dbh, `INSERT INTO segments(entry_id, started_at, ended_at) VALUES(?, ?, ?)`, paused, at(30*time.Hour), at(29*time.Hour))
+	resumed := seed(t, dbh, "resumed", at(30*time.Hour), "")
+	exec(t, dbh, `INSERT INTO segments(entry_id, started_at, ended_at) VALUES(?, ?, ?), (?, ?, NULL)`,
+		resumed, at(30*time.Hour), at(29*time.Hour), resumed, at(20*time.Hour))
+	fresh := seed(t, dbh, "fresh", at(30*time.Hour), "")
+	exec(t, dbh, `INSERT INTO segments(entry_id, started_at, ended_at) VALUES(?, ?, ?), (?, ?, NULL)`,
+		fresh, at(30*time.Hour), at(29*time.Hour), fresh, at(time.Hour))
+
+	o := Options{MaxTimer: 12 * time.Hour, Now: now}
+	want := map[string][]string{
+		"fts":        {"out of sync"},
+		"timestamps": {"#2: ts", "#3: ended_at"},
+		"tags":       {"#4:", "#5:"},
+		"timers":     {"#6 ", "#9 "},
+	}
+	check := func(r Report, fixed bool) {
+		t.Helper()
+		got := map[string][]string{}
+		for _, f := range r.Findings {
+			got[f.Check] = append(got[f.Check], f.Problem)
+			unfixable := strings.Contains(f.Problem, `"soon"`)
+			if f.Fixed != (fixed && !unfixable) {
+				t.Errorf("%s: %s fixed = %v", f.Check, f.Problem, f.Fixed)
+			}
+		}
+		for c, problems := range want {
+			if len(got[c]) != len(problems) {
+				t.Errorf("%s: found %q, want %d problems", c, got[c], len(problems))
+				continue
+			}
+			for i, p := range problems {
+				if !strings.Contains(got[c][i], p) {
+					t.Errorf("%s: problem %q, want it to mention %q", c, got[c][i], p)
+				}
+			}
+		}
+		if len(got) != len(want) {
+			t.Errorf("checks with findings: %v", got)
+		}
+	}
+
+	r, err := Run(dbh, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(r.Checks, ",") != "integrity,fts,timestamps,tags,timers" {
+		t.Errorf("checks = %v", r.Checks)
+	}
+	check(r, false)
+
+	o.Fix = true
+	if r, err = Run(dbh, o); err != nil {
+		t.Fatal(err)
+	}
+	check(r, true)
+
+	// Only the garbled timestamp is left
+	o.Fix = false
+	if r, err = Run(dbh, o); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Findings) != 1 || !strings.Contains(r.Findings[0].Problem, `"soon"`) {
+		t.Errorf("after fixing: %+v", r.Findings)
+	}
+
+	var n int
+	if err := dbh.QueryRow(`SELECT count(1) FROM entries_fts WHERE entries_fts MATCH 'ghost'`).Scan(&n); err != nil || n != 0 {
+		t.Errorf("ghost still in the search index: %d, %v", n, err)
+	}
+	if err := dbh.QueryRow(`SELECT count(1) FROM entries_fts WHERE entries_fts MATCH 'adopted OR x'`).Scan(&n); err != nil || n != 1 {
+		t.Errorf("adopted tags not searchable: %d, %v", n, err)
+	}
+
+	rows := []struct {
+		id           int64
+		ts, tags     string
+		ended        sql.NullString
+		openSegments int
+	}{
+		{ok, at(2 * time.Hour), "a", sql.NullString{String: at(time.Hour), Valid: true}, 0},
+		{local, "2025-10-01T09:00:00.000Z", "", sql.NullString{}, 0},
+		{columnOnly, at(time.Hour), "x,y", sql.NullString{}, 0},
+		{drifted, at(time.Hour), "a,b", sql.NullString{}, 0},
+		{forgotten, at(30 * time.Hour), "", sql.NullString{String: at(18 * time.Hour), Valid: true}, 0},
+		{paused, at(30 * time.Hour), "", sql.NullString{}, 0},
+		{resumed, at(30 * time.Hour), "", sql.NullString{String: at(8 * time.Hour), Valid: true}, 0},
+		{fresh, at(30 * time.Hour), "", sql.NullString{}, 1},
+	}
+	for _, w := range rows {
+		var ts, tags, links string
+		var ended sql.NullString
+		var open int
+		err := dbh.QueryRow(`SELECT CAST(ts AS TEXT), COALESCE(tags,''), ended_at,
+			COALESCE((SELECT group_concat(t.name, ',') FROM (SELECT t.name FROM entry_tags et JOIN tags t ON t.id = et.tag_id WHERE et.entry_id = e.id ORDER BY t.name) t), ''),
+			(SELECT count(1) FROM segments sg WHERE sg.entry_id = e.id AND sg.ended_at IS NULL)
+			FROM entries e WHERE id=?`, w.id).Scan(&ts, &tags, &ended, &links, &open)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts != w.ts || tags != w.tags || links != w.tags || ended != w.ended || open != w.openSegments {
+			t.Errorf("entry #%d: ts %s, tags %q/%q, ended %v, %d open segments; want %s, %q, %v, %d",
+				w.id, ts, tags, links, ended, open, w.ts, w.tags, w.ended, w.openSegments)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package export writes entries as a Markdown journal or an iCalendar feed.
+// CSV and JSON exports share the --output schema in package output.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Markdown writes entries (oldest first) as a journal with one section per
+// local day (beginning dayStart after midnight), each ending with the day's
+// tracked time. With a rounding policy, entries and day totals show rounded
+// time and the totals also what was tracked.
+func Markdown(w io.Writer, entries []model.Entry, loc *time.Location, dayStart time.Duration, r rounding.Policy, now time.Time) error {
+	if _, err := fmt.Fprintln(w, "# Pulse journal"); err != nil {
+		return err
+	}
+	var day string
+	var tracked rounding.Tally
+	flush := func() error {
+		if day == "" || tracked.Tracked == 0 {
+			return nil
+		}
+		if r.IsZero() {
+			_, err := fmt.Fprintf(w, "\n_Tracked: %s_\n", formatDuration(tracked.Tracked))
+			return err
+		}
+		_, err := fmt.Fprintf(w, "\n_Total: %s (rounded %s; tracked %s)_\n", formatDuration(tracked.Rounded()), r, formatDuration(tracked.Tracked))
+		return err
+	}
+	for _, e := range entries {
+		ts := e.TS.In(loc)
+		if d := timeparse.Day(ts, dayStart).Format("2006-01-02"); d != day {
+			if err := flush(); err != nil {
+				return err
+			}
+			day, tracked = d, rounding.Tally{Policy: r}
+			if _, err := fmt.Fprintf(w, "\n## %s (%s)\n\n", d, timeparse.Day(ts, dayStart).Weekday()); err != nil {
+				return err
+			}
+		}
+
+		meta := []string{"**" + ts.Format("15:04") + "**", e.Category}
+		if e.Project != "" {
+			meta = append(meta, "*"+e.Project+"*")
+		}
+		if len(e.Tags) > 0 {
+			meta = append(meta, "#"+strings.Join(e.Tags, " #"))
+		}
+		if e.StartedAt != nil {
+			d := e.Duration(now)
+			tracked.Add(timeparse.Day(ts, dayStart), d)
+			if !r.PerDay {
+				d = r.Round(d)
+			}
+			if e.Running() {
+				meta = append(meta, "running "+formatDuration(d))
+			} else {
+				meta = append(meta, formatDuration(d))
+			}
+		}
+		lines := strings.Split(strings.TrimSpace(e.Text), "\n")
+		if _, err := fmt.Fprintf(w, "- %s — %s\n", strings.Join(meta, " · "), lines[0]); err != nil {
+			return err
+		}
+		for _, l := range lines[1:] {
+			if _, err := fmt.Fprintf(w, "  %s\n", l); err != nil {
+				return err
+			}
+		}
+	}
+	return flush()
+}
+
+// formatDuration renders d as "2h05m" or "12m".
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if h := int(d / time.Hour); h > 0 {
+		return fmt.Sprintf("%dh%02dm", h, int(d%time.Hour/time.Minute))
+	}
+	return fmt.Sprintf("%dm", int(d/time.Minute))
+}
@@ -0,0 +1,152 @@
+package export
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+var (
+	berlin, _ = time.LoadLocation("Europe/Berlin")
+	now       = time.Date(2025, 10, 2, 12, 0, 0, 0, time.UTC)
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+// entries are oldest first, in Berlin time (UTC+2).
+func entries() []model.Entry {
+	at := func(d, h, m int) time.Time { return time.Date(2025, 10, d, h, m, 0, 0, berlin) }
+	return []model.Entry{
+		{ID: 1, TS: at(1, 9, 0), Category: "timer", Text: "api work\nwith notes", Project: "acme", Tags: []string{"dev", "prod"}, StartedAt: ptr(at(1, 9, 0)), EndedAt: ptr(at(1, 9, 50))},
+		{ID: 2, TS: at(1, 11, 0), Category: "note", Text: "call back"},
+		// Before the 4am day start, so still October 1st
+		{ID: 3, TS: at(2, 2, 0), Category: "meeting", Text: "late sync; notes, \\ and more on the quarterly roadmap, hiring and budget"},
+		{ID: 4, TS: at(2, 9, 0), Category: "timer", Text: "review", StartedAt: ptr(at(2, 9, 0))},
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		policy string
+		want   string
+	}{
+		{"", `# Pulse journal
+
+## 2025-10-01 (Wednesday)
+
+- **09:00** · timer · *acme* · #dev #prod · 50m — api work
+  with notes
+- **11:00** · note — call back
+- **02:00** · meeting — late sync; notes, \ and more on the quarterly roadmap, hiring and budget
+
+_Tracked: 50m_
+
+## 2025-10-02 (Thursday)
+
+- **09:00** · timer · running 5h00m — review
+
+_Tracked: 5h00m_
+`},
+		{"15m up", `# Pulse journal
+
+## 2025-10-01 (Wednesday)
+
+- **09:00** · timer · *acme* · #dev #prod · 1h00m — api work
+  with notes
+- **11:00** · note — call back
+- **02:00** · meeting — late sync; notes, \ and more on the quarterly roadmap, hiring and budget
+
+_Total: 1h00m (rounded 15m up per entry; tracked 50m)_
+
+## 2025-10-02 (Thursday)
+
+- **09:00** · timer · running 5h00m — review
+
+_Total: 5h00m (rounded 15m up per entry; tracked 5h00m)_
+`},
+	}
+	for _, tt := range tests {
+		r, err := rounding.Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b bytes.Buffer
+		if err := Markdown(&b, entries(), berlin, 4*time.Hour, r, now); err != nil {
+			t.Fatal(err)
+		}
+		if got := b.String(); got != tt.want {
+			t.Errorf("rounding %q:\n%s\nwant:\n%s", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestICS(t *testing.T) {
+	var b bytes.Buffer
+	if err := ICS(&b, entries(), now); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//pulse//pulse export//EN",
+		"CALSCALE:GREGORIAN",
+		"BEGIN:VEVENT",
+		"UID:pulse-entry-1@pulse",
+		"DTSTAMP:20251002T120000Z",
+		"DTSTART:20251001T070000Z",
+		"DTEND:20251001T075000Z",
+		"SUMMARY:acme: api work",
+		`DESCRIPTION:api work\nwith notes`,
+		"CATEGORIES:timer,dev,prod",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:pulse-entry-3@pulse",
+		"DTSTAMP:20251002T120000Z",
+		"DTSTART:20251002T000000Z",
+		`SUMMARY:late sync\; notes\, \\ and more on the quarterly roadmap\, hiring a`,
+		" nd budget",
+		`DESCRIPTION:late sync\; notes\, \\ and more on the quarterly roadmap\, hiri`,
+		" ng and budget",
+		"CATEGORIES:meeting",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:pulse-entry-4@pulse",
+		"DTSTAMP:20251002T120000Z",
+		"DTSTART:20251002T070000Z",
+		"DTEND:20251002T120000Z",
+		"SUMMARY:review",
+		"DESCRIPTION:review",
+		"CATEGORIES:timer",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+	if got := b.String(); got != want {
+		t.Errorf("ICS:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// Folding counts octets and never splits a character.
+func TestICSFolding(t *testing.T) {
+	line := "DESCRIPTION:" + strings.Repeat("ü", 100)
+	var b bytes.Buffer
+	c := &icsWriter{w: &b}
+	c.line(line)
+	physical := strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n")
+	if len(physical) != 3 {
+		t.Errorf("folded into %d lines, want 3", len(physical))
+	}
+	for _, l := range physical {
+		if len(l) > 75 || !utf8.ValidString(l) {
+			t.Errorf("line of %d octets: %q", len(l), l)
+		}
+	}
+	if got := strings.ReplaceAll(b.String(), "\r\n ", ""); got != line+"\r\n" {
+		t.Errorf("unfolded = %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+const icsTime = "20060102T150405Z"
+
+// ICS writes timers and meetings as an RFC 5545 calendar. Tracked entries
+// span started_at–ended_at (running timers end now); untimed meetings become
+// events at their timestamp. UIDs are stable so re-importing updates events
+// instead of duplicating them. Other entries are skipped.
+func ICS(w io.Writer, entries []model.Entry, now time.Time) error {
+	c := &icsWriter{w: w}
+	c.line("BEGIN:VCALENDAR")
+	c.line("VERSION:2.0")
+	c.line("PRODID:-//pulse//pulse export//EN")
+	c.line("CALSCALE:GREGORIAN")
+	for _, e := range entries {
+		if e.Category != "timer" && e.Category != "meeting" {
+			continue
+		}
+		start := e.TS
+		if e.StartedAt != nil {
+			start = *e.StartedAt
+		}
+		summary := strings.SplitN(strings.TrimSpace(e.Text), "\n", 2)[0]
+		if e.Project != "" {
+			summary = e.Project + ": " + summary
+		}
+
+		c.line("BEGIN:VEVENT")
+		c.line(fmt.Sprintf("UID:pulse-entry-%d@pulse", e.ID))
+		c.line("DTSTAMP:" + now.UTC().Format(icsTime))
+		c.line("DTSTART:" + start.UTC().Format(icsTime))
+		if e.StartedAt != nil {
+			end := now
+			if e.EndedAt != nil {
+				end = *e.EndedAt
+			}
+			c.line("DTEND:" + end.UTC().Format(icsTime))
+		}
+		c.line("SUMMARY:" + icsEscape(summary))
+		c.line("DESCRIPTION:" + icsEscape(e.Text))
+		cats := append([]string{e.Category}, e.Tags...)
+		for i := range cats {
+			cats[i] = icsEscape(cats[i])
+		}
+		c.line("CATEGORIES:" + strings.Join(cats, ","))
+		c.line("END:VEVENT")
+	}
+	c.line("END:VCALENDAR")
+	return c.err
+}
+
+// icsWriter writes CRLF-terminated content lines folded at 75 octets.
+type icsWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (c *icsWriter) line(s string) {
+	if c.err != nil {
+		return
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		size := len(string(r))
+		if n+size > 75 {
+			b.WriteString("\r\n ")
+			n = 1
+		}
+		b.WriteRune(r)
+		n += size
+	}
+	b.WriteString("\r\n")
+	_, c.err = io.WriteString(c.w, b.String())
+}
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func icsEscape(s string) string {
+	return icsEscaper.Replace(s)
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// csvRows reads a CSV file with a header row, yielding each record as a map
+// of normalized column name ("Start date" → "start_date") to value.
+func csvRows(r io.Reader, fn func(n int, row map[string]string)) error {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return fmt.Errorf("reading CSV header: %w", err)
+	}
+	for i, h := range header {
+		h = strings.TrimPrefix(h, "\ufeff") // BOM written by spreadsheet apps
+		header[i] = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(h)), " ", "_")
+	}
+	for n := 1; ; n++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		row := make(map[string]string, len(header))
+		for i, v := range rec {
+			if i < len(header) {
+				row[header[i]] = strings.TrimSpace(v)
+			}
+		}
+		fn(n, row)
+	}
+}
+
+// first returns the first non-empty value among keys.
+func first(row map[string]string, keys ...string) string {
+	for _, k := range keys {
+		if v := row[k]; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// readCSV accepts pulse's own CSV export and similar files: columns are
+// matched by name (ts, start/started_at, end/ended_at, duration or
+// duration_seconds, category, project, tags, text/description).
+func readCSV(r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	var out []store.Import
+	var skipped []Skipped
+	err := csvRows(r, func(n int, row map[string]string) {
+		imp, err := csvEntry(row, o)
+		if err != nil {
+			skipped = append(skipped, Skipped{n, err.Error()})
+			return
+		}
+		out = append(out, imp)
+	})
+	return out, skipped, err
+}
+
+func csvEntry(row map[string]string, o Options) (store.Import, error) {
+	var imp store.Import
+	parse := func(col string, keys ...string) (*time.Time, error) {
+		v := first(row, keys...)
+		if v == "" {
+			return nil, nil
+		}
+		t, err := parseTime(v, o.Loc)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", col, err)
+		}
+		return &t, nil
+	}
+	ts, err := parse("ts", "ts", "timestamp", "date")
+	if err != nil {
+		return imp, err
+	}
+	start, err := parse("start", "started_at", "start")
+	if err != nil {
+		return imp, err
+	}
+	end, err := parse("end", "ended_at", "end", "stop")
+	if err != nil {
+		return imp, err
+	}
+	if row["running"] == "true" {
+		return imp, fmt.Errorf("timer still running")
+	}
+	if start != nil && end == nil {
+		d, err := parseDuration(first(row, "duration_seconds", "duration"))
+		if err != nil {
+			return imp, err
+		}
+		if d == 0 {
+			return imp, fmt.Errorf("no end or duration (still running?)")
+		}
+		e := start.Add(d)
+		end = &e
+	}
+
+	imp.Category = first(row, "category")
+	imp.Project = first(row, "project")
+	imp.Tags = db.ParseTags(first(row, "tags"))
+	imp.Text = first(row, "text", "description", "note")
+	imp.NonBillable = row["billable"] == "false"
+	switch {
+	case start != nil:
+		if err := tracked(&imp, *start, *end); err != nil {
+			return imp, err
+		}
+		if ts != nil {
+			imp.TS = *ts
+		}
+	case ts != nil:
+		imp.TS = *ts
+	default:
+		return imp, fmt.Errorf("no ts or start column")
+	}
+	if imp.Category == "" {
+		imp.Category = "note"
+		if start != nil {
+			imp.Category = o.Category
+		}
+	}
+	if imp.Text == "" {
+		return imp, fmt.Errorf("empty text")
+	}
+	imp.Source = fingerprint("pulse", imp.TS.UTC().Format(time.RFC3339), imp.Category, imp.Project, imp.Text)
+	return imp, nil
+}
+
+// parseTime accepts RFC 3339 and stored timestamps (UTC), or local
+// "YYYY-MM-DD HH:MM[:SS]" and bare dates in loc.
+func parseTime(s string, loc *time.Location) (time.Time, error) {
+	if t, err := db.ParseTime(s); err == nil && strings.ContainsAny(s, "TZ") {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+// parseDuration accepts seconds ("2700"), Go durations ("45m") and clock
+// durations ("0:45:00", "1:30").
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseFloat(s, 64); err == nil && n >= 0 {
+		return time.Duration(n * float64(time.Second)), nil
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return d, nil
+	}
+	parts := strings.Split(s, ":")
+	if len(parts) == 2 || len(parts) == 3 {
+		var d time.Duration
+		units := []time.Duration{time.Hour, time.Minute, time.Second}
+		for i, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil || n < 0 {
+				return 0, fmt.Errorf("unrecognized duration %q", s)
+			}
+			d += time.Duration(n) * units[i]
+		}
+		return d, nil
+	}
+	return 0, fmt.Errorf("unrecognized duration %q", s)
+}
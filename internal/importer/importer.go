@@ -0,0 +1,71 @@
+// Package importer reads time-tracking history exported by pulse and other
+// tools into store.Import records.
+package importer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// Formats lists the accepted --format values.
+var Formats = []string{"csv", "json", "timewarrior", "toggl-csv", "watson"}
+
+// Options adjusts how records are mapped to entries.
+type Options struct {
+	Loc      *time.Location // for sources that store local wall-clock times
+	Category string         // category for tracked time from other trackers (default "timer")
+}
+
+// Skipped is a record that could not be imported, by 1-based position.
+type Skipped struct {
+	Record int
+	Reason string
+}
+
+// Read parses r in the given format. Records that cannot be mapped are
+// returned as Skipped rather than failing the whole import.
+func Read(format string, r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	if o.Loc == nil {
+		o.Loc = time.Local
+	}
+	if o.Category == "" {
+		o.Category = "timer"
+	}
+	switch format {
+	case "csv":
+		return readCSV(r, o)
+	case "json":
+		return readJSON(r, o)
+	case "timewarrior":
+		return readTimewarrior(r, o)
+	case "toggl-csv":
+		return readToggl(r, o)
+	case "watson":
+		return readWatson(r, o)
+	}
+	return nil, nil, fmt.Errorf("unknown import format %q (want %s)", format, strings.Join(Formats, ", "))
+}
+
+// fingerprint identifies a source record across imports: the format plus a
+// hash of the record's own id when it has one, otherwise of its content.
+func fingerprint(format string, parts ...string) string {
+	h := sha256.Sum256([]byte(strings.Join(parts, "\x1f")))
+	return format + ":" + hex.EncodeToString(h[:16])
+}
+
+// tracked fills start/end on imp, using the start as the entry timestamp. It
+// fails when end is before start rather than import a negative duration.
+func tracked(imp *store.Import, start, end time.Time) error {
+	if end.Before(start) {
+		return fmt.Errorf("end %s is before start %s", end.Format(time.RFC3339), start.Format(time.RFC3339))
+	}
+	imp.TS = start
+	imp.StartedAt, imp.EndedAt = &start, &end
+	return nil
+}
@@ -0,0 +1,218 @@
+package importer
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+var berlin = time.FixedZone("CEST", 2*60*60)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		format  string
+		in      string
+		skipped []int // record numbers
+		// first imported record
+		text     string
+		project  string
+		tags     []string
+		start    time.Time
+		duration time.Duration
+	}{
+		{
+			format: "csv",
+			in: "ts,category,project,tags,text,started_at,ended_at,duration_seconds\n" +
+				"2025-10-01T09:00:00Z,timer,acme,\"bug,prod\",fix login,2025-10-01T09:00:00Z,2025-10-01T09:45:00Z,2700\n" +
+				"2025-10-01T10:00:00Z,timer,acme,,reversed,2025-10-01T11:00:00Z,2025-10-01T10:00:00Z,\n" +
+				"2025-10-01 12:00,note,,,local note,,,\n" +
+				"2025-10-01T13:00:00Z,note,,,,,,\n",
+			skipped: []int{2, 4},
+			text:    "fix login", project: "acme", tags: []string{"bug", "prod"},
+			start: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC), duration: 45 * time.Minute,
+		},
+		{
+			format: "csv",
+			in: "start,duration,description\n" +
+				"2025-10-01 09:00,1:30,from a spreadsheet\n" +
+				"2025-10-01 11:00,,no end\n",
+			skipped: []int{2},
+			text:    "from a spreadsheet",
+			start:   time.Date(2025, 10, 1, 9, 0, 0, 0, berlin), duration: 90 * time.Minute,
+		},
+		{
+			format: "json",
+			in: `[{"ts":"2025-10-01T09:00:00Z","category":"timer","text":"json","project":"acme","tags":["#a","a","b"],"started_at":"2025-10-01T09:00:00Z","duration_seconds":600},
+				{"ts":"2025-10-01T10:00:00Z","text":"running","started_at":"2025-10-01T10:00:00Z","running":true},
+				{"ts":"2025-10-01T11:00:00Z","text":"reversed","started_at":"2025-10-01T11:00:00Z","ended_at":"2025-10-01T10:00:00Z"},
+				{"ts":"2025-10-01T12:00:00Z","text":""}]`,
+			skipped: []int{2, 3, 4},
+			text:    "json", project: "acme", tags: []string{"a", "b"},
+			start: time.Date(2025, 10, 1, 9, 0, 0, 0, time.UTC), duration: 10 * time.Minute,
+		},
+		{
+			format: "json",
+			in: `{"ts":"2025-10-01T09:00:00Z","text":"ndjson note"}
+				{"ts":"2025-10-01T10:00:00Z","text":"second"}`,
+			text: "ndjson note",
+		},
+		{
+			format: "timewarrior",
+			in: `[{"start":"20251001T070000Z","end":"20251001T073000Z","tags":["docs","acme"]},
+				{"start":"20251001T080000Z"},
+				{"start":"20251001T090000Z","end":"20251001T080000Z","annotation":"reversed"}]`,
+			skipped: []int{2, 3},
+			text:    "docs acme", tags: []string{"docs", "acme"},
+			start: time.Date(2025, 10, 1, 7, 0, 0, 0, time.UTC), duration: 30 * time.Minute,
+		},
+		{
+			format: "toggl-csv",
+			in: "User,Email,Project,Description,Billable,Start date,Start time,End date,End time,Tags\n" +
+				"me,me@example.com,acme,Standup,Yes,2025-10-01,09:00:00,2025-10-01,09:15:00,meeting\n" +
+				"me,me@example.com,acme,Reversed,Yes,2025-10-01,10:00:00,2025-10-01,09:00:00,\n" +
+				"me,me@example.com,acme,Broken,Yes,2025-10-01,soon,2025-10-01,09:00:00,\n",
+			skipped: []int{2, 3},
+			text:    "Standup", project: "acme", tags: []string{"meeting"},
+			start: time.Date(2025, 10, 1, 9, 0, 0, 0, berlin), duration: 15 * time.Minute,
+		},
+		{
+			format: "watson",
+			in: `[[1759302000, 1759305600, "acme", "f1", ["review"], 1759305600],
+				[1759309200, 1759305600, "acme", "f2", [], 1759309200],
+				[1759309200]]`,
+			skipped: []int{2, 3},
+			text:    "review", project: "acme", tags: []string{"review"},
+			start: time.Unix(1759302000, 0), duration: time.Hour,
+		},
+		{
+			format: "watson",
+			in:     `[{"id":"f1","project":"acme","start":"2025-10-01T09:00:00+02:00","stop":"2025-10-01T09:20:00+02:00","tags":[]}]`,
+			text:   "acme", project: "acme",
+			start: time.Date(2025, 10, 1, 9, 0, 0, 0, berlin), duration: 20 * time.Minute,
+		},
+	}
+	for i, tt := range tests {
+		recs, skipped, err := Read(tt.format, strings.NewReader(tt.in), Options{Loc: berlin})
+		if err != nil {
+			t.Errorf("%d %s: %v", i, tt.format, err)
+			continue
+		}
+		var nums []int
+		for _, s := range skipped {
+			nums = append(nums, s.Record)
+		}
+		if !slices.Equal(nums, tt.skipped) {
+			t.Errorf("%d %s: skipped %+v, want records %v", i, tt.format, skipped, tt.skipped)
+		}
+		if len(recs) == 0 {
+			t.Errorf("%d %s: nothing imported", i, tt.format)
+			continue
+		}
+		got := recs[0]
+		if got.Text != tt.text || got.Project != tt.project || !slices.Equal(got.Tags, tt.tags) || got.Source == "" {
+			t.Errorf("%d %s: first record %+v", i, tt.format, got)
+		}
+		if tt.duration == 0 {
+			if got.StartedAt != nil {
+				t.Errorf("%d %s: a note got a start", i, tt.format)
+			}
+			continue
+		}
+		if got.StartedAt == nil || !got.StartedAt.Equal(tt.start) || !got.TS.Equal(tt.start) || got.Duration(time.Now()) != tt.duration {
+			t.Errorf("%d %s: tracked %v from %v, want %v from %v", i, tt.format, got.Duration(time.Now()), got.StartedAt, tt.duration, tt.start)
+		}
+	}
+}
+
+func TestReadErrors(t *testing.T) {
+	tests := []struct{ format, in string }{
+		{"yaml", ""},
+		{"json", `[{`},
+		{"timewarrior", `{}`},
+		{"toggl-csv", "ts,text\n2025-10-01T09:00:00Z,not toggl\n"},
+		{"watson", `nope`},
+	}
+	for _, tt := range tests {
+		if _, _, err := Read(tt.format, strings.NewReader(tt.in), Options{}); err == nil {
+			t.Errorf("Read(%s, %q) succeeded", tt.format, tt.in)
+		}
+	}
+}
+
+func TestImportDuplicates(t *testing.T) {
+	in := `{"ts":"2025-10-01T09:00:00Z","category":"timer","text":"work","started_at":"2025-10-01T09:00:00Z","ended_at":"2025-10-01T10:00:00Z"}
+		{"ts":"2025-10-01T11:00:00.250Z","text":"note"}`
+	recs, _, err := Read("json", strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	for _, want := range []store.ImportResult{{Added: 2}, {Duplicates: 2}} {
+		res, err := st.ImportEntries(recs, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != want {
+			t.Errorf("ImportEntries = %+v, want %+v", res, want)
+		}
+	}
+
+	// The same entries exported as CSV are duplicates too
+	csv := "ts,category,text,started_at,ended_at\n" +
+		"2025-10-01T09:00:00Z,timer,work,2025-10-01T09:00:00Z,2025-10-01T10:00:00Z\n" +
+		"2025-10-01T11:00:00Z,note,note,,\n"
+	recs, _, err = Read("csv", strings.NewReader(csv), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res, err := st.ImportEntries(recs, true); err != nil || res != (store.ImportResult{Duplicates: 2}) {
+		t.Errorf("ImportEntries of the CSV export = %+v, %v", res, err)
+	}
+}
+
+func TestImportSegments(t *testing.T) {
+	in := `{"ts":"2025-10-01T09:00:00Z","category":"timer","text":"paused","started_at":"2025-10-01T09:00:00Z","ended_at":"2025-10-01T10:00:00Z","duration_seconds":2400,"segments":[{"started_at":"2025-10-01T09:00:00Z","ended_at":"2025-10-01T09:20:00Z"},{"started_at":"2025-10-01T09:40:00Z","ended_at":"2025-10-01T10:00:00Z"}]}
+		{"ts":"2025-10-01T11:00:00Z","text":"open","started_at":"2025-10-01T11:00:00Z","ended_at":"2025-10-01T12:00:00Z","segments":[{"started_at":"2025-10-01T11:00:00Z"}]}
+		{"ts":"2025-10-01T13:00:00Z","text":"reversed","started_at":"2025-10-01T13:00:00Z","ended_at":"2025-10-01T14:00:00Z","segments":[{"started_at":"2025-10-01T13:30:00Z","ended_at":"2025-10-01T13:00:00Z"}]}`
+	recs, skipped, err := Read("json", strings.NewReader(in), Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || len(skipped) != 2 {
+		t.Fatalf("imported %d, skipped %+v", len(recs), skipped)
+	}
+	if d := recs[0].Duration(time.Now()); d != 40*time.Minute {
+		t.Errorf("imported duration = %v, want 40m", d)
+	}
+
+	st, err := store.Open(filepath.Join(t.TempDir(), "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer st.Close()
+	for _, want := range []store.ImportResult{{Added: 1}, {Duplicates: 1}} {
+		res, err := st.ImportEntries(recs, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res != want {
+			t.Errorf("ImportEntries = %+v, want %+v", res, want)
+		}
+	}
+	entries, err := st.ListEntries(store.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || len(entries[0].Segments) != 2 || entries[0].Duration(time.Now()) != 40*time.Minute {
+		t.Errorf("stored %+v", entries)
+	}
+}
@@ -0,0 +1,115 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// jsonEntry is the --output/export schema; only the fields needed to
+// recreate an entry are read.
+type jsonEntry struct {
+	TS              *time.Time `json:"ts"`
+	Category        string     `json:"category"`
+	Text            string     `json:"text"`
+	Project         string     `json:"project"`
+	Tags            []string   `json:"tags"`
+	StartedAt       *time.Time `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	DurationSeconds int64      `json:"duration_seconds"`
+	Running         bool       `json:"running"`
+	Billable        *bool      `json:"billable"`
+	// Segments keep the pauses of a timer out of its duration.
+	Segments []model.Segment `json:"segments"`
+}
+
+// readJSON accepts pulse's JSON export (an array) or NDJSON output.
+func readJSON(r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	var recs []json.RawMessage
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &recs); err != nil {
+			return nil, nil, fmt.Errorf("parsing JSON: %w", err)
+		}
+	} else {
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		sc.Buffer(nil, 16<<20)
+		for sc.Scan() {
+			if line := bytes.TrimSpace(sc.Bytes()); len(line) > 0 {
+				recs = append(recs, json.RawMessage(bytes.Clone(line)))
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var out []store.Import
+	var skipped []Skipped
+	for i, raw := range recs {
+		var je jsonEntry
+		if err := json.Unmarshal(raw, &je); err != nil {
+			skipped = append(skipped, Skipped{i + 1, err.Error()})
+			continue
+		}
+		imp, reason := je.entry(o)
+		if reason != "" {
+			skipped = append(skipped, Skipped{i + 1, reason})
+			continue
+		}
+		out = append(out, imp)
+	}
+	return out, skipped, nil
+}
+
+func (je jsonEntry) entry(o Options) (store.Import, string) {
+	var imp store.Import
+	switch {
+	case je.Running:
+		return imp, "timer still running"
+	case je.TS == nil:
+		return imp, "missing ts"
+	case je.Text == "":
+		return imp, "empty text"
+	}
+	imp.TS = *je.TS
+	imp.Category, imp.Text, imp.Project = je.Category, je.Text, je.Project
+	imp.Tags = db.ParseTags(db.JoinTags(je.Tags))
+	imp.NonBillable = je.Billable != nil && !*je.Billable
+	if je.StartedAt != nil {
+		end := je.StartedAt.Add(time.Duration(je.DurationSeconds) * time.Second)
+		if je.EndedAt != nil {
+			end = *je.EndedAt
+		}
+		if err := tracked(&imp, *je.StartedAt, end); err != nil {
+			return imp, err.Error()
+		}
+		imp.TS = *je.TS
+		for _, sg := range je.Segments {
+			switch {
+			case sg.EndedAt == nil:
+				return imp, "segment still running"
+			case sg.EndedAt.Before(sg.StartedAt):
+				return imp, "segment ends before it starts"
+			}
+		}
+		imp.Segments = je.Segments
+	}
+	if imp.Category == "" {
+		imp.Category = "note"
+	}
+	// Same fingerprint as readCSV (whose timestamps have second precision),
+	// so CSV and JSON exports of one entry match
+	imp.Source = fingerprint("pulse", imp.TS.UTC().Format(time.RFC3339), imp.Category, imp.Project, imp.Text)
+	return imp, ""
+}
@@ -0,0 +1,64 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// twInterval is one interval of `timew export`.
+type twInterval struct {
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Tags       []string `json:"tags"`
+	Annotation string   `json:"annotation"`
+}
+
+const twTime = "20060102T150405Z"
+
+// readTimewarrior reads `timew export` output. Timewarrior has no projects
+// or descriptions: tags carry over as tags and the annotation (or the tags)
+// becomes the text.
+func readTimewarrior(r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	var intervals []twInterval
+	if err := json.NewDecoder(r).Decode(&intervals); err != nil {
+		return nil, nil, fmt.Errorf("parsing timew export: %w", err)
+	}
+	var out []store.Import
+	var skipped []Skipped
+	for i, iv := range intervals {
+		if iv.End == "" {
+			skipped = append(skipped, Skipped{i + 1, "interval still open"})
+			continue
+		}
+		start, err1 := time.Parse(twTime, iv.Start)
+		end, err2 := time.Parse(twTime, iv.End)
+		if err1 != nil || err2 != nil {
+			skipped = append(skipped, Skipped{i + 1, fmt.Sprintf("bad interval %s–%s", iv.Start, iv.End)})
+			continue
+		}
+		imp := store.Import{}
+		imp.Category = o.Category
+		imp.Tags = db.ParseTags(db.JoinTags(iv.Tags))
+		imp.Text = iv.Annotation
+		if imp.Text == "" {
+			imp.Text = strings.Join(iv.Tags, " ")
+		}
+		if imp.Text == "" {
+			imp.Text = "(untagged)"
+		}
+		if err := tracked(&imp, start, end); err != nil {
+			skipped = append(skipped, Skipped{i + 1, err.Error()})
+			continue
+		}
+		// Intervals never overlap, so the start identifies one
+		imp.Source = fingerprint("timewarrior", iv.Start)
+		out = append(out, imp)
+	}
+	return out, skipped, nil
+}
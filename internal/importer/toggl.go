@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// readToggl reads a Toggl Track "detailed report" CSV. Its start/end are
+// local wall-clock times, interpreted in o.Loc.
+func readToggl(r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	var out []store.Import
+	var skipped []Skipped
+	notToggl := false
+	err := csvRows(r, func(n int, row map[string]string) {
+		if _, ok := row["start_date"]; !ok {
+			notToggl = true
+			return
+		}
+		start, err1 := time.ParseInLocation("2006-01-02 15:04:05", row["start_date"]+" "+row["start_time"], o.Loc)
+		end, err2 := time.ParseInLocation("2006-01-02 15:04:05", row["end_date"]+" "+row["end_time"], o.Loc)
+		if err1 != nil || err2 != nil {
+			skipped = append(skipped, Skipped{n, fmt.Sprintf("bad start/end %s %s – %s %s",
+				row["start_date"], row["start_time"], row["end_date"], row["end_time"])})
+			return
+		}
+		imp := store.Import{}
+		imp.Category = o.Category
+		imp.Project = row["project"]
+		imp.Tags = db.ParseTags(row["tags"])
+		imp.Text = row["description"]
+		imp.NonBillable = strings.EqualFold(row["billable"], "no")
+		if imp.Text == "" {
+			imp.Text = "(no description)"
+		}
+		if err := tracked(&imp, start, end); err != nil {
+			skipped = append(skipped, Skipped{n, err.Error()})
+			return
+		}
+		imp.Source = fingerprint("toggl", row["email"], db.FormatTime(start), db.FormatTime(end), imp.Project, imp.Text)
+		out = append(out, imp)
+	})
+	if err == nil && notToggl {
+		err = fmt.Errorf("not a Toggl detailed report (expected Start date, Start time, End date and End time columns)")
+	}
+	return out, skipped, err
+}
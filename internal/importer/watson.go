@@ -0,0 +1,98 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/store"
+)
+
+// watsonFrame is one frame of `watson log --json`.
+type watsonFrame struct {
+	ID      string   `json:"id"`
+	Project string   `json:"project"`
+	Start   string   `json:"start"`
+	Stop    string   `json:"stop"`
+	Tags    []string `json:"tags"`
+}
+
+// readWatson reads Watson's frames file (~/.config/watson/frames, a list of
+// [start, stop, project, id, tags, updated_at]) or `watson log --json`.
+// Frames carry no description, so the text is the tags or the project.
+func readWatson(r io.Reader, o Options) ([]store.Import, []Skipped, error) {
+	var raw []json.RawMessage
+	if err := json.NewDecoder(r).Decode(&raw); err != nil {
+		return nil, nil, fmt.Errorf("parsing Watson frames: %w", err)
+	}
+	var out []store.Import
+	var skipped []Skipped
+	for i, msg := range raw {
+		f, start, stop, err := decodeWatsonFrame(msg)
+		if err != nil {
+			skipped = append(skipped, Skipped{i + 1, err.Error()})
+			continue
+		}
+		imp := store.Import{}
+		imp.Category = o.Category
+		imp.Project = f.Project
+		imp.Tags = db.ParseTags(db.JoinTags(f.Tags))
+		imp.Text = strings.Join(f.Tags, " ")
+		if imp.Text == "" {
+			imp.Text = f.Project
+		}
+		if imp.Text == "" {
+			imp.Text = "(no description)"
+		}
+		if err := tracked(&imp, start, stop); err != nil {
+			skipped = append(skipped, Skipped{i + 1, err.Error()})
+			continue
+		}
+		key := f.ID
+		if key == "" {
+			key = db.FormatTime(start)
+		}
+		imp.Source = fingerprint("watson", key)
+		out = append(out, imp)
+	}
+	return out, skipped, nil
+}
+
+func decodeWatsonFrame(msg json.RawMessage) (watsonFrame, time.Time, time.Time, error) {
+	var f watsonFrame
+	var start, stop time.Time
+	// Frames file: [start, stop, project, id, tags, updated_at]
+	var arr []json.RawMessage
+	if err := json.Unmarshal(msg, &arr); err == nil {
+		if len(arr) < 4 {
+			return f, start, stop, fmt.Errorf("frame has %d fields, want at least 4", len(arr))
+		}
+		var s, e int64
+		if err := json.Unmarshal(arr[0], &s); err != nil {
+			return f, start, stop, fmt.Errorf("frame start: %w", err)
+		}
+		if err := json.Unmarshal(arr[1], &e); err != nil {
+			return f, start, stop, fmt.Errorf("frame stop: %w", err)
+		}
+		json.Unmarshal(arr[2], &f.Project)
+		json.Unmarshal(arr[3], &f.ID)
+		if len(arr) > 4 {
+			json.Unmarshal(arr[4], &f.Tags)
+		}
+		return f, time.Unix(s, 0), time.Unix(e, 0), nil
+	}
+	if err := json.Unmarshal(msg, &f); err != nil {
+		return f, start, stop, err
+	}
+	var err error
+	if start, err = time.Parse(time.RFC3339, f.Start); err != nil {
+		return f, start, stop, fmt.Errorf("frame start: %w", err)
+	}
+	if stop, err = time.Parse(time.RFC3339, f.Stop); err != nil {
+		return f, start, stop, fmt.Errorf("frame stop: %w", err)
+	}
+	return f, start, stop, nil
+}
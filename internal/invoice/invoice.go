@@ -0,0 +1,125 @@
+// Package invoice turns a period of billable tracked time into an invoice
+// rendered as Markdown or HTML.
+package invoice
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Line is one invoiced timer, or one day of them under a per-day policy.
+type Line struct {
+	EntryID     int64     // the first entry of the line
+	Date        time.Time // start, in the invoice's timezone
+	Description string
+	Tracked     time.Duration
+	Billed      time.Duration // Tracked after rounding
+	Rate        float64
+	Amount      float64
+}
+
+// Invoice is everything the templates render.
+type Invoice struct {
+	Number   string
+	Issued   time.Time
+	From     string // issuer block from the config, may span lines
+	Client   string
+	Project  string
+	Start    time.Time
+	End      time.Time // exclusive
+	Currency string
+	Rounding rounding.Policy
+	Lines    []Line
+	Tracked  time.Duration
+	Billed   time.Duration
+	Total    float64
+}
+
+// Build collects the billable, finished timers among entries (which should
+// already be limited to the project and period) as line items, oldest first.
+// A rated tag overrides the project's rate; with several, the highest wins.
+// With a per-day policy, each day's entries at the same rate become one line
+// so the day's total can be rounded. Days are taken in loc and begin dayStart
+// after midnight. Running timers are left out and returned separately so
+// callers can warn.
+func Build(entries []model.Entry, p model.Project, tagRates map[string]float64, r rounding.Policy, loc *time.Location, dayStart time.Duration) (*Invoice, []model.Entry, error) {
+	inv := &Invoice{Client: p.Client, Project: p.Name, Currency: p.Currency, Rounding: r}
+	var running []model.Entry
+	entries = slices.Clone(entries)
+	slices.SortStableFunc(entries, func(a, b model.Entry) int { return a.TS.Compare(b.TS) })
+	for _, e := range entries {
+		switch {
+		case e.StartedAt == nil || e.NonBillable:
+			continue
+		case e.Running():
+			running = append(running, e)
+			continue
+		}
+		rate, tagged := p.Rate, false
+		for _, t := range e.Tags {
+			if tr, ok := tagRates[t]; ok && (!tagged || tr > rate) {
+				rate, tagged = tr, true
+			}
+		}
+		if rate <= 0 {
+			return nil, running, fmt.Errorf("no hourly rate for project %s (pulse project add %s --rate 120)", p.Name, p.Name)
+		}
+		desc := strings.TrimSpace(strings.SplitN(strings.TrimSpace(e.Text), "\n", 2)[0])
+		date := e.StartedAt.In(loc)
+		if r.PerDay {
+			day := timeparse.Day(date, dayStart)
+			if i := slices.IndexFunc(inv.Lines, func(l Line) bool { return l.Rate == rate && timeparse.Day(l.Date, dayStart).Equal(day) }); i >= 0 {
+				l := &inv.Lines[i]
+				if desc != "" && !slices.Contains(strings.Split(l.Description, "; "), desc) {
+					l.Description = strings.TrimPrefix(l.Description+"; "+desc, "; ")
+				}
+				l.Tracked += e.Duration(time.Time{})
+				continue
+			}
+		}
+		inv.Lines = append(inv.Lines, Line{EntryID: e.ID, Date: date, Description: desc, Tracked: e.Duration(time.Time{}), Rate: rate})
+	}
+	for i := range inv.Lines {
+		l := &inv.Lines[i]
+		l.Billed = r.Round(l.Tracked)
+		l.Amount = cents(l.Billed.Hours() * l.Rate)
+		inv.Tracked += l.Tracked
+		inv.Billed += l.Billed
+		inv.Total += l.Amount
+	}
+	inv.Total = cents(inv.Total)
+	return inv, running, nil
+}
+
+func cents(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Hours renders d as decimal hours, e.g. "1.25".
+func Hours(d time.Duration) string {
+	return fmt.Sprintf("%.2f", d.Hours())
+}
+
+// Money renders v with thousands separators, e.g. "1,234.50".
+func Money(v float64) string {
+	s := fmt.Sprintf("%.2f", math.Abs(v))
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	if v < 0 {
+		b.WriteByte('-')
+	}
+	for i, c := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+	return b.String() + "." + frac
+}
@@ -0,0 +1,163 @@
+package invoice
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+// worked is a finished timer on 2025-09-d from h:00 for d minutes.
+func worked(id int64, day, h int, d time.Duration, text string, tags ...string) model.Entry {
+	start := time.Date(2025, 9, day, h, 0, 0, 0, time.UTC)
+	return model.Entry{ID: id, TS: start, Category: "timer", Text: text, Project: "acme", Tags: tags,
+		StartedAt: ptr(start), EndedAt: ptr(start.Add(d))}
+}
+
+func TestBuild(t *testing.T) {
+	p := model.Project{Name: "acme", Client: "ACME", Rate: 100, Currency: "EUR"}
+	rates := map[string]float64{"urgent": 150, "review": 120, "cheap": 50}
+
+	running := worked(6, 3, 9, 0, "still going")
+	running.EndedAt = nil
+	nonBillable := worked(7, 3, 10, time.Hour, "internal")
+	nonBillable.NonBillable = true
+	note := model.Entry{ID: 8, TS: time.Date(2025, 9, 3, 11, 0, 0, 0, time.UTC), Category: "note", Text: "untimed", Project: "acme"}
+	entries := []model.Entry{
+		worked(1, 2, 9, 90*time.Minute, "plain\nsecond line"),
+		worked(2, 1, 9, time.Hour, "review urgent", "review", "urgent", "other"),
+		worked(3, 1, 14, time.Hour, "cheap", "cheap"),
+		worked(4, 2, 14, 10*time.Minute, "ten minutes"),
+		worked(5, 2, 15, 20*time.Minute, "rounded up", "review"),
+		running, nonBillable, note,
+	}
+
+	tests := []struct {
+		name    string
+		r       rounding.Policy
+		lines   []Line // EntryID, Billed, Rate and Amount
+		desc    string // of the third line
+		billed  time.Duration
+		total   float64
+		tracked time.Duration
+	}{
+		{"as tracked", rounding.Policy{}, []Line{
+			{EntryID: 2, Billed: time.Hour, Rate: 150, Amount: 150},
+			{EntryID: 3, Billed: time.Hour, Rate: 50, Amount: 50},
+			{EntryID: 1, Billed: 90 * time.Minute, Rate: 100, Amount: 150},
+			{EntryID: 4, Billed: 10 * time.Minute, Rate: 100, Amount: 16.67},
+			{EntryID: 5, Billed: 20 * time.Minute, Rate: 120, Amount: 40},
+		}, "plain", 4 * time.Hour, 406.67, 4 * time.Hour},
+		{"15m up", rounding.Policy{Increment: 15 * time.Minute, Mode: "up"}, []Line{
+			{EntryID: 2, Billed: time.Hour, Rate: 150, Amount: 150},
+			{EntryID: 3, Billed: time.Hour, Rate: 50, Amount: 50},
+			{EntryID: 1, Billed: 90 * time.Minute, Rate: 100, Amount: 150},
+			{EntryID: 4, Billed: 15 * time.Minute, Rate: 100, Amount: 25},
+			{EntryID: 5, Billed: 30 * time.Minute, Rate: 120, Amount: 60},
+		}, "plain", 255 * time.Minute, 435, 4 * time.Hour},
+		// The second day's two entries at 100 merge into one line of 100m
+		{"15m up per day", rounding.Policy{Increment: 15 * time.Minute, Mode: "up", PerDay: true}, []Line{
+			{EntryID: 2, Billed: time.Hour, Rate: 150, Amount: 150},
+			{EntryID: 3, Billed: time.Hour, Rate: 50, Amount: 50},
+			{EntryID: 1, Billed: 105 * time.Minute, Rate: 100, Amount: 175},
+			{EntryID: 5, Billed: 30 * time.Minute, Rate: 120, Amount: 60},
+		}, "plain; ten minutes", 255 * time.Minute, 435, 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		inv, run, err := Build(entries, p, rates, tt.r, time.UTC, 0)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(run) != 1 || run[0].ID != running.ID {
+			t.Errorf("%s: running = %+v", tt.name, run)
+		}
+		if len(inv.Lines) != len(tt.lines) {
+			t.Fatalf("%s: %d lines, want %d: %+v", tt.name, len(inv.Lines), len(tt.lines), inv.Lines)
+		}
+		for i, want := range tt.lines {
+			got := inv.Lines[i]
+			if got.EntryID != want.EntryID || got.Billed != want.Billed || got.Rate != want.Rate || got.Amount != want.Amount {
+				t.Errorf("%s: line %d = %+v, want %+v", tt.name, i, got, want)
+			}
+		}
+		if inv.Lines[2].Description != tt.desc {
+			t.Errorf("%s: description %q, want %q", tt.name, inv.Lines[2].Description, tt.desc)
+		}
+		if inv.Billed != tt.billed || inv.Total != tt.total || inv.Tracked != tt.tracked {
+			t.Errorf("%s: billed %v, total %v, tracked %v; want %v, %v, %v", tt.name, inv.Billed, inv.Total, inv.Tracked, tt.billed, tt.total, tt.tracked)
+		}
+		if inv.Client != "ACME" || inv.Currency != "EUR" {
+			t.Errorf("%s: client %q, currency %q", tt.name, inv.Client, inv.Currency)
+		}
+	}
+
+	if _, _, err := Build(entries[:1], model.Project{Name: "acme"}, nil, rounding.Policy{}, time.UTC, 0); err == nil {
+		t.Error("Build without any rate succeeded")
+	}
+	if _, _, err := Build(entries[2:3], model.Project{Name: "acme"}, rates, rounding.Policy{}, time.UTC, 0); err != nil {
+		t.Errorf("Build with only a tag rate: %v", err)
+	}
+}
+
+func TestBuildDayStart(t *testing.T) {
+	// 23:00–23:30 and 02:00–02:30 the next night are one day with a 04:00 day start
+	entries := []model.Entry{worked(1, 2, 23, 30*time.Minute, "late"), worked(2, 3, 2, 30*time.Minute, "later")}
+	p := model.Project{Name: "acme", Rate: 100}
+	policy := rounding.Policy{Increment: time.Hour, Mode: "up", PerDay: true}
+	tests := []struct {
+		dayStart time.Duration
+		lines    int
+		total    float64
+	}{
+		{0, 2, 200},
+		{4 * time.Hour, 1, 100},
+	}
+	for _, tt := range tests {
+		inv, _, err := Build(entries, p, nil, policy, time.UTC, tt.dayStart)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(inv.Lines) != tt.lines || inv.Total != tt.total {
+			t.Errorf("day start %v: %d lines totalling %v, want %d totalling %v", tt.dayStart, len(inv.Lines), inv.Total, tt.lines, tt.total)
+		}
+	}
+}
+
+func TestCents(t *testing.T) {
+	tests := []struct{ in, want float64 }{
+		{16.666666, 16.67},
+		{16.664, 16.66},
+		{0.005, 0.01},
+		{150, 150},
+	}
+	for _, tt := range tests {
+		if got := cents(tt.in); got != tt.want {
+			t.Errorf("cents(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		in   float64
+		want string
+	}{
+		{0, "0.00"},
+		{999.5, "999.50"},
+		{1000, "1,000.00"},
+		{1234.5, "1,234.50"},
+		{100000, "100,000.00"},
+		{1234567.891, "1,234,567.89"},
+		{-42, "-42.00"},
+		{-1234.5, "-1,234.50"},
+		{-999999.99, "-999,999.99"},
+	}
+	for _, tt := range tests {
+		if got := Money(tt.in); got != tt.want {
+			t.Errorf("Money(%v) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
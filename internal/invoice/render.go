@@ -0,0 +1,114 @@
+package invoice
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Markdown writes inv as a Markdown document with a line item table.
+func Markdown(w io.Writer, inv *Invoice) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Invoice %s\n\n", inv.Number)
+	if inv.From != "" {
+		for _, l := range strings.Split(strings.TrimSpace(inv.From), "\n") {
+			fmt.Fprintf(&b, "%s  \n", strings.TrimSpace(l))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "**Date:** %s  \n", inv.Issued.Format("2006-01-02"))
+	if inv.Client != "" {
+		fmt.Fprintf(&b, "**Client:** %s  \n", inv.Client)
+	}
+	fmt.Fprintf(&b, "**Project:** %s  \n", inv.Project)
+	fmt.Fprintf(&b, "**Period:** %s  \n\n", period(inv))
+
+	b.WriteString("| Date | Description | Hours | Rate | Amount |\n")
+	b.WriteString("|---|---|---:|---:|---:|\n")
+	for _, l := range inv.Lines {
+		desc := strings.ReplaceAll(l.Description, "|", `\|`)
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", l.Date.Format("2006-01-02"), desc, Hours(l.Billed), Money(l.Rate), Money(l.Amount))
+	}
+	fmt.Fprintf(&b, "| | **Total** | **%s** | | **%s %s** |\n", Hours(inv.Billed), inv.Currency, Money(inv.Total))
+	if !inv.Rounding.IsZero() {
+		fmt.Fprintf(&b, "\n_%s (%s h tracked)._\n", roundingNote(inv), Hours(inv.Tracked))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// HTML writes inv as a standalone, printable HTML page.
+func HTML(w io.Writer, inv *Invoice) error {
+	return htmlTemplate.Execute(w, struct {
+		*Invoice
+		Period string
+		Note   string
+		From   []string
+	}{inv, period(inv), roundingNote(inv), strings.Split(strings.TrimSpace(inv.From), "\n")})
+}
+
+// roundingNote explains the policy, e.g. "Each entry rounded up to 15m".
+func roundingNote(inv *Invoice) string {
+	unit := "Each entry"
+	if inv.Rounding.PerDay {
+		unit = "Each day"
+	}
+	return fmt.Sprintf("%s rounded %s to %s", unit, inv.Rounding.Mode, inv.Rounding.Step())
+}
+
+// period renders the invoiced days, e.g. "2025-09-01 – 2025-09-30".
+func period(inv *Invoice) string {
+	return inv.Start.Format("2006-01-02") + " – " + inv.End.AddDate(0, 0, -1).Format("2006-01-02")
+}
+
+var htmlTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"hours": Hours,
+	"money": Money,
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Number}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; max-width: 800px; margin: 2em auto; color: #222; }
+h1 { margin-bottom: 0.2em; }
+.from { color: #555; margin-bottom: 1.5em; }
+dl { display: grid; grid-template-columns: max-content auto; gap: 0.2em 1em; }
+dt { font-weight: bold; }
+dd { margin: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 1.5em; }
+th, td { padding: 0.4em 0.6em; border-bottom: 1px solid #ddd; text-align: left; }
+.num { text-align: right; white-space: nowrap; }
+tfoot td { font-weight: bold; border-top: 2px solid #222; border-bottom: none; }
+.note { color: #777; font-size: 0.9em; margin-top: 1em; }
+</style>
+</head>
+<body>
+<h1>Invoice {{.Number}}</h1>
+{{- if .Invoice.From}}
+<div class="from">{{range .From}}{{.}}<br>{{end}}</div>
+{{- end}}
+<dl>
+<dt>Date</dt><dd>{{.Issued.Format "2006-01-02"}}</dd>
+{{- if .Client}}
+<dt>Client</dt><dd>{{.Client}}</dd>
+{{- end}}
+<dt>Project</dt><dd>{{.Project}}</dd>
+<dt>Period</dt><dd>{{.Period}}</dd>
+</dl>
+<table>
+<thead><tr><th>Date</th><th>Description</th><th class="num">Hours</th><th class="num">Rate</th><th class="num">Amount</th></tr></thead>
+<tbody>
+{{- range .Lines}}
+<tr><td>{{.Date.Format "2006-01-02"}}</td><td>{{.Description}}</td><td class="num">{{hours .Billed}}</td><td class="num">{{money .Rate}}</td><td class="num">{{money .Amount}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot><tr><td></td><td>Total</td><td class="num">{{hours .Billed}}</td><td></td><td class="num">{{.Currency}} {{money .Total}}</td></tr></tfoot>
+</table>
+{{- if not .Rounding.IsZero}}
+<p class="note">{{.Note}} ({{hours .Tracked}} h tracked).</p>
+{{- end}}
+</body>
+</html>
+`))
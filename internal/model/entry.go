@@ -1,11 +1,71 @@
 package model
 
+import "time"
+
+// Entry is one log line. The JSON names are part of the documented
+// --output schema; add fields, never rename them.
 type Entry struct {
-	ID              int64
-	TS              string
-	Category        string
-	Text            string
-	Project         string
-	Tags            string
-	DurationMinutes int
+	ID        int64      `json:"id"`
+	TS        time.Time  `json:"ts"`
+	Category  string     `json:"category"`
+	Text      string     `json:"text"`
+	Project   string     `json:"project"`
+	Tags      []string   `json:"tags"`
+	StartedAt *time.Time `json:"started_at"`           // nil for untimed entries
+	EndedAt   *time.Time `json:"ended_at"`             // nil while a timer is running
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // set while the entry is in the trash
+	// NonBillable keeps tracked time off invoices. Entries are billable by
+	// default; --output reports this as "billable".
+	NonBillable bool `json:"-"`
+	// Segments are the stretches of work of a timer that has been paused,
+	// oldest first; a timer that never was has none.
+	Segments []Segment `json:"segments,omitempty"`
+}
+
+// Segment is a stretch of work on a timer between pauses.
+type Segment struct {
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"` // nil while running
+}
+
+// Running reports whether the entry is a timer that has not been stopped.
+// Paused timers are still running.
+func (e Entry) Running() bool {
+	return e.StartedAt != nil && e.EndedAt == nil
+}
+
+// Paused reports whether the entry is a running timer on a pause.
+func (e Entry) Paused() bool {
+	return e.Running() && len(e.Segments) > 0 && e.Segments[len(e.Segments)-1].EndedAt != nil
+}
+
+// Duration is the tracked time of the entry; running timers count up to now.
+// A timer with segments tracks their sum, leaving out its pauses.
+func (e Entry) Duration(now time.Time) time.Duration {
+	if e.StartedAt == nil {
+		return 0
+	}
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if len(e.Segments) == 0 {
+		return span(*e.StartedAt, end)
+	}
+	var d time.Duration
+	for _, s := range e.Segments {
+		if s.EndedAt != nil {
+			d += span(s.StartedAt, *s.EndedAt)
+		} else {
+			d += span(s.StartedAt, end)
+		}
+	}
+	return d
+}
+
+func span(start, end time.Time) time.Duration {
+	if end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
 }
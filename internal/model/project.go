@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Project is a registered project. Entries refer to it by Name.
+type Project struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	Client     string     `json:"client"`
+	Color      string     `json:"color"`                 // "#89B4FA" or an ANSI color number; "" for the default
+	Rate       float64    `json:"rate"`                  // hourly rate for invoices; 0 when unset
+	Currency   string     `json:"currency"`              // "" for invoice.currency from the config
+	ArchivedAt *time.Time `json:"archived_at,omitempty"` // archived projects are hidden and warned about
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Budget caps the project's tracked time overall, PeriodBudget each
+	// BudgetPeriod ("week" or "month"); 0 means no budget.
+	Budget       time.Duration `json:"-"`
+	PeriodBudget time.Duration `json:"-"`
+	BudgetPeriod string        `json:"budget_period"`
+}
+
+// BudgetPeriods are the accepted BudgetPeriod values.
+var BudgetPeriods = []string{"week", "month"}
+
+// Archived reports whether the project has been archived.
+func (p Project) Archived() bool {
+	return p.ArchivedAt != nil
+}
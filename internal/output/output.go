@@ -0,0 +1,261 @@
+// Package output renders command results in the machine-readable formats
+// selected with --output. The JSON, NDJSON and CSV schemas are documented in
+// the README and only ever grow: fields are added, never renamed or removed.
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+type Format string
+
+const (
+	Table  Format = "table"  // styled, for people (the default)
+	Plain  Format = "plain"  // tab-separated text without styling
+	JSON   Format = "json"   // one JSON array
+	NDJSON Format = "ndjson" // one JSON object per line
+	CSV    Format = "csv"    // header row plus one record per row
+)
+
+// Formats lists every accepted --output value.
+var Formats = []Format{Table, Plain, JSON, NDJSON, CSV}
+
+func ParseFormat(s string) (Format, error) {
+	for _, f := range Formats {
+		if strings.EqualFold(s, string(f)) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q (want table, plain, json, ndjson or csv)", s)
+}
+
+// Machine reports whether f is a structured format handled by Write.
+func (f Format) Machine() bool {
+	return f == JSON || f == NDJSON || f == CSV
+}
+
+// IsTerminal reports whether f is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+// Row is a value Write can render as CSV; JSON uses its struct tags.
+type Row interface {
+	CSVHeader() []string
+	CSVRecord() []string
+}
+
+// Write renders rows in a machine format.
+func Write[T Row](w io.Writer, f Format, rows []T) error {
+	switch f {
+	case JSON:
+		if rows == nil {
+			rows = []T{}
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	case NDJSON:
+		enc := json.NewEncoder(w)
+		for _, r := range rows {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return nil
+	case CSV:
+		cw := csv.NewWriter(w)
+		var zero T
+		if err := cw.Write(zero.CSVHeader()); err != nil {
+			return err
+		}
+		for _, r := range rows {
+			if err := cw.Write(r.CSVRecord()); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+	return fmt.Errorf("%s is not a machine-readable format", f)
+}
+
+// Entry is the machine-readable form of an entry: model.Entry plus its
+// computed duration. Times are UTC RFC 3339. RoundedSeconds is the duration
+// under a per-entry rounding policy (pulse export), else DurationSeconds.
+type Entry struct {
+	model.Entry
+	DurationSeconds int64 `json:"duration_seconds"`
+	Running         bool  `json:"running"`
+	Billable        bool  `json:"billable"`
+	RoundedSeconds  int64 `json:"rounded_seconds"`
+	Paused          bool  `json:"paused"`
+}
+
+// NewEntry prepares e for output, counting running timers up to now.
+func NewEntry(e model.Entry, now time.Time) Entry {
+	if e.Tags == nil {
+		e.Tags = []string{}
+	}
+	e.Segments = slices.Clone(e.Segments)
+	for i, sg := range e.Segments {
+		e.Segments[i].StartedAt = sg.StartedAt.UTC()
+		if sg.EndedAt != nil {
+			u := sg.EndedAt.UTC()
+			e.Segments[i].EndedAt = &u
+		}
+	}
+	e.TS = e.TS.UTC()
+	for _, t := range []**time.Time{&e.StartedAt, &e.EndedAt, &e.DeletedAt} {
+		if *t != nil {
+			u := (*t).UTC()
+			*t = &u
+		}
+	}
+	secs := int64(e.Duration(now) / time.Second)
+	return Entry{Entry: e, DurationSeconds: secs, Running: e.Running(), Billable: !e.NonBillable, RoundedSeconds: secs, Paused: e.Paused()}
+}
+
+var entryHeader = []string{"id", "ts", "category", "project", "tags", "text", "started_at", "ended_at", "duration_seconds", "running", "billable", "rounded_seconds", "paused"}
+
+func (Entry) CSVHeader() []string { return entryHeader }
+
+func (e Entry) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(e.ID, 10),
+		stamp(&e.TS),
+		e.Category,
+		e.Project,
+		strings.Join(e.Tags, ","),
+		e.Text,
+		stamp(e.StartedAt),
+		stamp(e.EndedAt),
+		strconv.FormatInt(e.DurationSeconds, 10),
+		strconv.FormatBool(e.Running),
+		strconv.FormatBool(e.Billable),
+		strconv.FormatInt(e.RoundedSeconds, 10),
+		strconv.FormatBool(e.Paused),
+	}
+}
+
+// SearchResult is an Entry with the FTS snippet ("[" and "]" around matches)
+// and bm25 rank (lower is better).
+type SearchResult struct {
+	Entry
+	Snippet string  `json:"snippet"`
+	Rank    float64 `json:"rank"`
+}
+
+func (SearchResult) CSVHeader() []string {
+	return append(entryHeader[:len(entryHeader):len(entryHeader)], "snippet", "rank")
+}
+
+func (r SearchResult) CSVRecord() []string {
+	return append(r.Entry.CSVRecord(), r.Snippet, strconv.FormatFloat(r.Rank, 'f', -1, 64))
+}
+
+// CategoryTotal is one line of pulse summary.
+type CategoryTotal struct {
+	Category        string `json:"category"`
+	Count           int    `json:"count"`
+	DurationSeconds int64  `json:"duration_seconds"`
+}
+
+func (CategoryTotal) CSVHeader() []string { return []string{"category", "count", "duration_seconds"} }
+
+func (t CategoryTotal) CSVRecord() []string {
+	return []string{t.Category, strconv.Itoa(t.Count), strconv.FormatInt(t.DurationSeconds, 10)}
+}
+
+func stamp(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Project is one line of pulse project list. Registered is false for names
+// used by entries that were never added with pulse project add.
+type Project struct {
+	model.Project
+	Registered          bool  `json:"registered"`
+	Entries             int   `json:"entries"`
+	DurationSeconds     int64 `json:"duration_seconds"`
+	BudgetSeconds       int64 `json:"budget_seconds"`
+	PeriodBudgetSeconds int64 `json:"period_budget_seconds"`
+}
+
+func (Project) CSVHeader() []string {
+	return []string{"id", "name", "client", "color", "archived_at", "registered", "entries", "duration_seconds", "rate", "currency",
+		"budget_seconds", "period_budget_seconds", "budget_period"}
+}
+
+func (p Project) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(p.ID, 10),
+		p.Name,
+		p.Client,
+		p.Color,
+		stamp(p.ArchivedAt),
+		strconv.FormatBool(p.Registered),
+		strconv.Itoa(p.Entries),
+		strconv.FormatInt(p.DurationSeconds, 10),
+		strconv.FormatFloat(p.Rate, 'f', -1, 64),
+		p.Currency,
+		strconv.FormatInt(p.BudgetSeconds, 10),
+		strconv.FormatInt(p.PeriodBudgetSeconds, 10),
+		p.BudgetPeriod,
+	}
+}
+
+// Tag is one line of pulse tags.
+type Tag struct {
+	Name     string    `json:"name"`
+	Entries  int       `json:"entries"`
+	LastUsed time.Time `json:"last_used"`
+	Rate     float64   `json:"rate"`
+}
+
+func (Tag) CSVHeader() []string { return []string{"name", "entries", "last_used", "rate"} }
+
+func (t Tag) CSVRecord() []string {
+	return []string{t.Name, strconv.Itoa(t.Entries), stamp(&t.LastUsed), strconv.FormatFloat(t.Rate, 'f', -1, 64)}
+}
+
+// Goal is one line of pulse goals: a budget or goal and its use so far.
+type Goal struct {
+	Project          string     `json:"project"` // "" for the weekly goal across all projects
+	Period           string     `json:"period"`  // total, week or month
+	Since            *time.Time `json:"since"`   // null for total budgets
+	BudgetSeconds    int64      `json:"budget_seconds"`
+	UsedSeconds      int64      `json:"used_seconds"`
+	RemainingSeconds int64      `json:"remaining_seconds"` // negative when over budget
+	Percent          float64    `json:"percent"`
+}
+
+func (Goal) CSVHeader() []string {
+	return []string{"project", "period", "since", "budget_seconds", "used_seconds", "remaining_seconds", "percent"}
+}
+
+func (g Goal) CSVRecord() []string {
+	return []string{
+		g.Project,
+		g.Period,
+		stamp(g.Since),
+		strconv.FormatInt(g.BudgetSeconds, 10),
+		strconv.FormatInt(g.UsedSeconds, 10),
+		strconv.FormatInt(g.RemainingSeconds, 10),
+		strconv.FormatFloat(g.Percent, 'f', 1, 64),
+	}
+}
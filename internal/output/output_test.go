@@ -0,0 +1,68 @@
+package output
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+// The machine formats are a public schema: these goldens change only by
+// adding fields.
+
+func results() []SearchResult {
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+	cet := time.FixedZone("CET", 3600)
+	start := time.Date(2025, 10, 15, 9, 0, 0, 0, cet)
+	end := start.Add(90 * time.Minute)
+	pause := start.Add(time.Hour)
+	resume := now.Add(-10 * time.Minute)
+	return []SearchResult{
+		{
+			Entry:   NewEntry(model.Entry{ID: 7, TS: start, Category: "task", Text: "fix login, \"again\"", Project: "acme", Tags: []string{"bug", "prod"}, StartedAt: &start, EndedAt: &end}, now),
+			Snippet: "fix [login]",
+			Rank:    -1.5,
+		},
+		{
+			Entry: NewEntry(model.Entry{ID: 8, TS: start, Category: "timer", Text: "review", NonBillable: true, StartedAt: &start,
+				Segments: []model.Segment{{StartedAt: start, EndedAt: &pause}, {StartedAt: resume}}}, now),
+			Rank: 0,
+		},
+	}
+}
+
+func TestWriteCSV(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, CSV, results()); err != nil {
+		t.Fatal(err)
+	}
+	want := `id,ts,category,project,tags,text,started_at,ended_at,duration_seconds,running,billable,rounded_seconds,paused,snippet,rank
+7,2025-10-15T08:00:00Z,task,acme,"bug,prod","fix login, ""again""",2025-10-15T08:00:00Z,2025-10-15T09:30:00Z,5400,false,true,5400,false,fix [login],-1.5
+8,2025-10-15T08:00:00Z,timer,,,review,2025-10-15T08:00:00Z,,4200,true,false,4200,false,,0
+`
+	if got := b.String(); got != want {
+		t.Errorf("CSV:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, NDJSON, results()); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"id":7,"ts":"2025-10-15T08:00:00Z","category":"task","text":"fix login, \"again\"","project":"acme","tags":["bug","prod"],"started_at":"2025-10-15T08:00:00Z","ended_at":"2025-10-15T09:30:00Z","duration_seconds":5400,"running":false,"billable":true,"rounded_seconds":5400,"paused":false,"snippet":"fix [login]","rank":-1.5}
+{"id":8,"ts":"2025-10-15T08:00:00Z","category":"timer","text":"review","project":"","tags":[],"started_at":"2025-10-15T08:00:00Z","ended_at":null,"segments":[{"started_at":"2025-10-15T08:00:00Z","ended_at":"2025-10-15T09:00:00Z"},{"started_at":"2025-10-15T11:50:00Z","ended_at":null}],"duration_seconds":4200,"running":true,"billable":false,"rounded_seconds":4200,"paused":false,"snippet":"","rank":0}
+`
+	if got := b.String(); got != want {
+		t.Errorf("NDJSON:\n%s\nwant:\n%s", got, want)
+	}
+
+	b.Reset()
+	if err := Write[SearchResult](&b, JSON, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "[]\n" {
+		t.Errorf("JSON without rows = %q, want an empty array", got)
+	}
+}
@@ -0,0 +1,74 @@
+package report
+
+import (
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Goal is a time budget and how much of it is used.
+type Goal struct {
+	Project string    // "" for the weekly goal across all projects
+	Period  string    // "total", "week" or "month"
+	Since   time.Time // start of the current period; zero for "total"
+	Budget  time.Duration
+	Used    time.Duration
+}
+
+// Percent is how much of the budget is used; over 100 when overrun.
+func (g Goal) Percent() float64 {
+	return Percent(g.Used, g.Budget)
+}
+
+// Remaining is the budget left, negative when overrun.
+func (g Goal) Remaining() time.Duration {
+	return g.Budget - g.Used
+}
+
+// Crossed returns the highest of the thresholds (percentages, e.g. 80 and
+// 100) that g passed since it stood at before, and whether there was one.
+func (g Goal) Crossed(before time.Duration, thresholds []int) (int, bool) {
+	was, now := Percent(before, g.Budget), g.Percent()
+	best, ok := 0, false
+	for _, t := range thresholds {
+		if was < float64(t) && now >= float64(t) && (!ok || t > best) {
+			best, ok = t, true
+		}
+	}
+	return best, ok
+}
+
+// Used sums the time tracked by timed entries starting at or after since (all
+// of them when since is zero), rounded by r. Days are taken in loc and begin
+// dayStart after midnight.
+func Used(entries []model.Entry, since time.Time, loc *time.Location, dayStart time.Duration, r rounding.Policy, now time.Time) time.Duration {
+	t := rounding.Tally{Policy: r}
+	for _, e := range entries {
+		if e.StartedAt == nil || e.TS.Before(since) {
+			continue
+		}
+		t.Add(timeparse.Day(e.TS.In(loc), dayStart), e.Duration(now))
+	}
+	return t.Rounded()
+}
+
+// ProjectGoals measures p's budgets against entries, which should hold all of
+// p's entries. week and month are the starts of the current week and month.
+func ProjectGoals(p model.Project, entries []model.Entry, week, month time.Time, loc *time.Location, dayStart time.Duration, r rounding.Policy, now time.Time) []Goal {
+	var goals []Goal
+	if p.Budget > 0 {
+		goals = append(goals, Goal{Project: p.Name, Period: "total", Budget: p.Budget,
+			Used: Used(entries, time.Time{}, loc, dayStart, r, now)})
+	}
+	if p.PeriodBudget > 0 {
+		since := week
+		if p.BudgetPeriod == "month" {
+			since = month
+		}
+		goals = append(goals, Goal{Project: p.Name, Period: p.BudgetPeriod, Since: since, Budget: p.PeriodBudget,
+			Used: Used(entries, since, loc, dayStart, r, now)})
+	}
+	return goals
+}
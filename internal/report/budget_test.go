@@ -0,0 +1,103 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+func TestGoalCrossed(t *testing.T) {
+	g := Goal{Budget: 10 * time.Hour, Used: 9 * time.Hour}
+	alerts := []int{80, 100}
+	tests := []struct {
+		used, before time.Duration
+		want         int
+		ok           bool
+	}{
+		{9 * time.Hour, 7 * time.Hour, 80, true},
+		{8 * time.Hour, 7 * time.Hour, 80, true}, // reaching a threshold crosses it
+		{11 * time.Hour, 7 * time.Hour, 100, true},
+		{11 * time.Hour, 9 * time.Hour, 100, true},
+		{9 * time.Hour, 8 * time.Hour, 0, false}, // already past 80
+		{12 * time.Hour, 11 * time.Hour, 0, false},
+		{7 * time.Hour, 6 * time.Hour, 0, false},
+		{0, 0, 0, false},
+	}
+	for _, tt := range tests {
+		g.Used = tt.used
+		got, ok := g.Crossed(tt.before, alerts)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%v → %v of %v: Crossed = %d, %v; want %d, %v", tt.before, tt.used, g.Budget, got, ok, tt.want, tt.ok)
+		}
+	}
+	// Thresholds need not be sorted
+	g.Used = 11 * time.Hour
+	if got, ok := g.Crossed(0, []int{100, 50, 80}); got != 100 || !ok {
+		t.Errorf("unsorted thresholds: Crossed = %d, %v", got, ok)
+	}
+	if got, ok := (Goal{Used: time.Hour}).Crossed(0, alerts); ok {
+		t.Errorf("Crossed without a budget = %d", got)
+	}
+}
+
+func TestUsed(t *testing.T) {
+	entries := []model.Entry{
+		worked(1, 9, 20, "acme"),
+		worked(1, 14, 20, "acme"),
+		worked(2, 2, 20, "acme"), // October 1st with a 4am day start
+		worked(3, 9, 50, "acme"),
+		{TS: time.Date(2025, 10, 3, 10, 0, 0, 0, time.UTC), Category: "note", Project: "acme"},
+	}
+	oct := func(d, h int) time.Time { return time.Date(2025, 10, d, h, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		since  time.Time
+		policy string
+		want   time.Duration
+	}{
+		{time.Time{}, "", 110 * time.Minute},
+		{oct(1, 12), "", 90 * time.Minute},
+		{oct(2, 4), "", 50 * time.Minute},
+		{time.Time{}, "15m up", 150 * time.Minute},           // 30+30+30+60
+		{time.Time{}, "1h up per day", 2 * time.Hour},        // 60m on the 1st, 50m on the 3rd
+		{time.Time{}, "1h down per day", time.Hour},          // the 3rd rounds to nothing
+		{oct(1, 12), "1h up per day", 2 * time.Hour},         // 40m on the 1st, 50m on the 3rd
+		{oct(2, 1), "30m nearest per day", 90 * time.Minute}, // 20m on the 1st, 50m on the 3rd
+	}
+	for _, tt := range tests {
+		r, err := rounding.Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Used(entries, tt.since, time.UTC, 4*time.Hour, r, now); got != tt.want {
+			t.Errorf("Used since %v, rounding %q = %v, want %v", tt.since, tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestProjectGoals(t *testing.T) {
+	entries := []model.Entry{worked(1, 9, 60, "acme"), worked(6, 9, 90, "acme")}
+	week := time.Date(2025, 10, 6, 4, 0, 0, 0, time.UTC)
+	month := time.Date(2025, 10, 1, 4, 0, 0, 0, time.UTC)
+	p := model.Project{Name: "acme", Budget: 10 * time.Hour, PeriodBudget: 2 * time.Hour, BudgetPeriod: "week"}
+	got := ProjectGoals(p, entries, week, month, time.UTC, 4*time.Hour, rounding.Policy{}, now)
+	want := []Goal{
+		{Project: "acme", Period: "total", Budget: 10 * time.Hour, Used: 150 * time.Minute},
+		{Project: "acme", Period: "week", Since: week, Budget: 2 * time.Hour, Used: 90 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProjectGoals = %+v, want %+v", got, want)
+	}
+
+	p.Budget, p.BudgetPeriod = 0, "month"
+	got = ProjectGoals(p, entries, week, month, time.UTC, 4*time.Hour, rounding.Policy{}, now)
+	want = []Goal{{Project: "acme", Period: "month", Since: month, Budget: 2 * time.Hour, Used: 150 * time.Minute}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("monthly ProjectGoals = %+v, want %+v", got, want)
+	}
+	if got[0].Remaining() != -30*time.Minute || got[0].Percent() != 125 {
+		t.Errorf("remaining %v at %.1f%%", got[0].Remaining(), got[0].Percent())
+	}
+}
@@ -0,0 +1,157 @@
+// Package report totals tracked time over nested groupings (project, tag,
+// category, day) for pulse report.
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Dimensions are the accepted --group-by levels.
+var Dimensions = []string{"project", "tag", "category", "day"}
+
+// Group is a node of the report tree; the root holds the grand total.
+type Group struct {
+	Key      string
+	Duration time.Duration // after rounding
+	Tracked  time.Duration // as tracked
+	Count    int
+	Groups   []*Group // next level, empty at the leaves
+
+	tally rounding.Tally
+}
+
+// ParseGroupBy splits and validates a --group-by list such as "project,day".
+func ParseGroupBy(s string) ([]string, error) {
+	var by []string
+	for _, d := range strings.Split(s, ",") {
+		d = strings.ToLower(strings.TrimSpace(d))
+		if d == "" {
+			continue
+		}
+		if one := strings.TrimSuffix(d, "s"); slices.Contains(Dimensions, one) {
+			d = one // "tags", "projects"
+		}
+		if !slices.Contains(Dimensions, d) {
+			return nil, fmt.Errorf("cannot group by %q (want %s)", d, strings.Join(Dimensions, ", "))
+		}
+		if slices.Contains(by, d) {
+			return nil, fmt.Errorf("%s is listed twice in --group-by", d)
+		}
+		by = append(by, d)
+	}
+	return by, nil
+}
+
+// Build groups entries level by level. An entry with several tags counts
+// toward each of them, so tag groups can add up to more than their parent.
+// Days are taken in loc and begin dayStart after midnight. Every group's
+// Duration is rounded by r on its own, so with rounding the children of a
+// group need not add up to it either.
+func Build(entries []model.Entry, by []string, loc *time.Location, dayStart time.Duration, r rounding.Policy, now time.Time) *Group {
+	root := &Group{Key: "TOTAL", tally: rounding.Tally{Policy: r}}
+	cal := day{loc, dayStart}
+	for _, e := range entries {
+		d, start := e.Duration(now), timeparse.Day(e.TS.In(loc), dayStart)
+		root.tally.Add(start, d)
+		root.Count++
+		add(root, e, d, start, by, cal)
+	}
+	total(root)
+	sortGroups(root, by)
+	return root
+}
+
+func add(g *Group, e model.Entry, d time.Duration, start time.Time, by []string, cal day) {
+	if len(by) == 0 {
+		return
+	}
+	for _, key := range keys(e, by[0], cal) {
+		child := g.child(key)
+		child.tally.Add(start, d)
+		child.Count++
+		add(child, e, d, start, by[1:], cal)
+	}
+}
+
+func (g *Group) child(key string) *Group {
+	for _, c := range g.Groups {
+		if c.Key == key {
+			return c
+		}
+	}
+	c := &Group{Key: key, tally: rounding.Tally{Policy: g.tally.Policy}}
+	g.Groups = append(g.Groups, c)
+	return c
+}
+
+// total fills in Duration and Tracked from the tallies.
+func total(g *Group) {
+	g.Duration, g.Tracked = g.tally.Rounded(), g.tally.Tracked
+	for _, c := range g.Groups {
+		total(c)
+	}
+}
+
+// day says where days begin for the "day" dimension.
+type day struct {
+	loc   *time.Location
+	start time.Duration
+}
+
+func keys(e model.Entry, dim string, cal day) []string {
+	switch dim {
+	case "project":
+		if e.Project == "" {
+			return []string{"(no project)"}
+		}
+		return []string{e.Project}
+	case "tag":
+		if len(e.Tags) == 0 {
+			return []string{"(untagged)"}
+		}
+		return e.Tags
+	case "category":
+		return []string{e.Category}
+	case "day":
+		return []string{timeparse.Day(e.TS.In(cal.loc), cal.start).Format("2006-01-02")}
+	}
+	return nil
+}
+
+// sortGroups orders days chronologically and everything else by time spent.
+func sortGroups(g *Group, by []string) {
+	if len(by) == 0 {
+		return
+	}
+	slices.SortStableFunc(g.Groups, func(a, b *Group) int {
+		if by[0] == "day" {
+			return strings.Compare(a.Key, b.Key)
+		}
+		return cmp.Or(cmp.Compare(b.Duration, a.Duration), strings.Compare(a.Key, b.Key))
+	})
+	for _, c := range g.Groups {
+		sortGroups(c, by[1:])
+	}
+}
+
+// Percent is d as a percentage of total (0 when total is 0).
+func Percent(d, total time.Duration) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return float64(d) / float64(total) * 100
+}
+
+// FormatHM renders d as hours:minutes, e.g. "12:05".
+func FormatHM(d time.Duration) string {
+	m := int(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%d:%02d", m/60, m%60)
+}
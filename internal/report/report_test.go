@@ -0,0 +1,152 @@
+package report
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+func ptr(t time.Time) *time.Time { return &t }
+
+// worked is an entry of d minutes starting at day/hour (UTC, October 2025).
+func worked(day, hour, d int, project string, tags ...string) model.Entry {
+	start := time.Date(2025, 10, day, hour, 0, 0, 0, time.UTC)
+	return model.Entry{TS: start, Category: "timer", Text: "work", Project: project, Tags: tags,
+		StartedAt: ptr(start), EndedAt: ptr(start.Add(time.Duration(d) * time.Minute))}
+}
+
+var now = time.Date(2025, 10, 10, 0, 0, 0, 0, time.UTC)
+
+// flatten renders g as "key duration count" lines, children indented.
+func flatten(g *Group, indent string, out *[]string) {
+	for _, c := range g.Groups {
+		*out = append(*out, fmt.Sprintf("%s%s %s %d", indent, c.Key, FormatHM(c.Duration), c.Count))
+		flatten(c, indent+"  ", out)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	entries := []model.Entry{
+		worked(2, 9, 50, "acme", "dev"),
+		worked(1, 9, 30, "acme", "dev", "ops"),
+		worked(1, 13, 20, ""),
+		worked(2, 2, 10, "beta"), // before the 4am day start: October 1st
+		worked(1, 15, 70, "beta", "ops"),
+	}
+	tests := []struct {
+		by     []string
+		policy string
+		total  string
+		want   []string
+	}{
+		{[]string{"project", "day"}, "", "3:00", []string{
+			"acme 1:20 2",
+			"  2025-10-01 0:30 1",
+			"  2025-10-02 0:50 1",
+			"beta 1:20 2",
+			"  2025-10-01 1:20 2",
+			"(no project) 0:20 1",
+			"  2025-10-01 0:20 1",
+		}},
+		// A tag group counts every entry carrying the tag.
+		{[]string{"day", "tag"}, "", "3:00", []string{
+			"2025-10-01 2:10 4",
+			"  ops 1:40 2",
+			"  (untagged) 0:30 2",
+			"  dev 0:30 1",
+			"2025-10-02 0:50 1",
+			"  dev 0:50 1",
+		}},
+		// Rounded per entry, then totalled per group.
+		{[]string{"project", "day"}, "1h up", "6:00", []string{
+			"beta 3:00 2",
+			"  2025-10-01 3:00 2",
+			"acme 2:00 2",
+			"  2025-10-01 1:00 1",
+			"  2025-10-02 1:00 1",
+			"(no project) 1:00 1",
+			"  2025-10-01 1:00 1",
+		}},
+		// Each group rounds its own day totals, so beta's two entries on
+		// October 1st share one increment.
+		{[]string{"project", "day"}, "1h up per day", "4:00", []string{
+			"acme 2:00 2",
+			"  2025-10-01 1:00 1",
+			"  2025-10-02 1:00 1",
+			"beta 2:00 2",
+			"  2025-10-01 2:00 2",
+			"(no project) 1:00 1",
+			"  2025-10-01 1:00 1",
+		}},
+	}
+	for _, tt := range tests {
+		r, err := rounding.Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		root := Build(entries, tt.by, time.UTC, 4*time.Hour, r, now)
+		var got []string
+		flatten(root, "", &got)
+		if FormatHM(root.Duration) != tt.total || root.Count != len(entries) ||
+			strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+			t.Errorf("by %v, rounding %q: total %s of %d\n%s\nwant total %s\n%s",
+				tt.by, tt.policy, FormatHM(root.Duration), root.Count, strings.Join(got, "\n"), tt.total, strings.Join(tt.want, "\n"))
+		}
+		if root.Tracked != 3*time.Hour {
+			t.Errorf("by %v, rounding %q: tracked %v", tt.by, tt.policy, root.Tracked)
+		}
+	}
+}
+
+func TestPercent(t *testing.T) {
+	entries := []model.Entry{
+		worked(1, 9, 10, "a"),
+		worked(1, 10, 20, "b"),
+		worked(2, 9, 25, "b"),
+		worked(1, 11, 35, "c"),
+	}
+	root := Build(entries, []string{"project", "day"}, time.UTC, 0, rounding.Policy{}, now)
+	var sum float64
+	for _, p := range root.Groups {
+		pct := Percent(p.Duration, root.Duration)
+		sum += pct
+		var days float64
+		for _, d := range p.Groups {
+			days += Percent(d.Duration, root.Duration)
+		}
+		if math.Abs(days-pct) > 1e-9 {
+			t.Errorf("%s: days add up to %.4f%%, want %.4f%%", p.Key, days, pct)
+		}
+	}
+	if math.Abs(sum-100) > 1e-9 {
+		t.Errorf("projects add up to %.4f%%", sum)
+	}
+	if got := Percent(time.Hour, 0); got != 0 {
+		t.Errorf("Percent of nothing = %v", got)
+	}
+}
+
+func TestFormatHM(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0:00"},
+		{29 * time.Second, "0:00"},
+		{30 * time.Second, "0:01"},
+		{59 * time.Minute, "0:59"},
+		{time.Hour, "1:00"},
+		{12*time.Hour + 5*time.Minute, "12:05"},
+		{100*time.Hour + 59*time.Minute + 31*time.Second, "101:00"},
+	}
+	for _, tt := range tests {
+		if got := FormatHM(tt.d); got != tt.want {
+			t.Errorf("FormatHM(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
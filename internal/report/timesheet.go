@@ -0,0 +1,119 @@
+package report
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+	"github.com/ramanasai/pulse/internal/timeparse"
+)
+
+// Sheet is a week of tracked time as a project × day grid.
+type Sheet struct {
+	Start time.Time   // first day of the week (Monday, plus any day start)
+	Days  []time.Time // columns: the workdays, plus other days with tracked time
+	Rows  []SheetRow  // by time spent, largest first
+	Daily []time.Duration
+	Total time.Duration
+
+	// Tracked is Total before rounding; cells and totals are rounded.
+	Tracked time.Duration
+}
+
+// SheetRow is one project's time per column of a Sheet.
+type SheetRow struct {
+	Project string
+	Days    []time.Duration
+	Total   time.Duration
+	Tracked time.Duration // Total before rounding
+}
+
+// Week returns the label of s's week, e.g. "2025-W40".
+func (s *Sheet) Week() string {
+	y, w := s.Start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", y, w)
+}
+
+// Timesheet fills the week beginning at start with tracked entries. Notes
+// without a timer are ignored. Days not in workdays ("Mon", "Tue"…) only get
+// a column when something was tracked on them, so totals never lose time.
+// Each cell is rounded by r, per entry or as the day's total.
+func Timesheet(entries []model.Entry, start time.Time, workdays []string, dayStart time.Duration, r rounding.Policy, now time.Time) *Sheet {
+	var week [7]time.Time
+	for i := range week {
+		week[i] = start.AddDate(0, 0, i)
+	}
+	cells := map[string]*[7]rounding.Tally{}
+	var used [7]bool
+	for _, e := range entries {
+		if e.StartedAt == nil {
+			continue
+		}
+		day := timeparse.Day(e.TS.In(start.Location()), dayStart)
+		i := slices.IndexFunc(week[:], day.Equal)
+		if i < 0 {
+			continue
+		}
+		p := e.Project
+		if p == "" {
+			p = "(no project)"
+		}
+		if cells[p] == nil {
+			cells[p] = new([7]rounding.Tally)
+			for j := range cells[p] {
+				cells[p][j].Policy = r
+			}
+		}
+		cells[p][i].Add(day, e.Duration(now))
+		used[i] = true
+	}
+
+	s := &Sheet{Start: start}
+	var cols []int
+	for i, d := range week {
+		if used[i] || slices.Contains(workdays, d.Format("Mon")) {
+			cols = append(cols, i)
+			s.Days = append(s.Days, d)
+		}
+	}
+	s.Daily = make([]time.Duration, len(cols))
+	for p, c := range cells {
+		row := SheetRow{Project: p, Days: make([]time.Duration, len(cols))}
+		for j, i := range cols {
+			d := c[i].Rounded()
+			row.Days[j] = d
+			row.Total += d
+			row.Tracked += c[i].Tracked
+			s.Daily[j] += d
+		}
+		s.Total += row.Total
+		s.Tracked += row.Tracked
+		s.Rows = append(s.Rows, row)
+	}
+	slices.SortFunc(s.Rows, func(a, b SheetRow) int {
+		return cmp.Or(cmp.Compare(b.Total, a.Total), strings.Compare(a.Project, b.Project))
+	})
+	return s
+}
+
+// ParseWeek resolves an ISO week ("2025-W40", "2025w40") to its Monday in loc.
+func ParseWeek(s string, loc *time.Location) (time.Time, error) {
+	y, w, ok := strings.Cut(strings.ToUpper(strings.TrimSpace(s)), "W")
+	year, err1 := strconv.Atoi(strings.TrimSuffix(y, "-"))
+	week, err2 := strconv.Atoi(w)
+	if !ok || err1 != nil || err2 != nil || week < 1 || week > 53 {
+		return time.Time{}, fmt.Errorf("unrecognized week %q (want e.g. 2025-W40)", s)
+	}
+	// January 4th is always in week 1.
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+7*(week-1))
+	if _, got := monday.ISOWeek(); got != week {
+		return time.Time{}, fmt.Errorf("%d has no week %d", year, week)
+	}
+	return monday, nil
+}
@@ -0,0 +1,80 @@
+package report
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+	"github.com/ramanasai/pulse/internal/rounding"
+)
+
+func TestTimesheet(t *testing.T) {
+	at := func(m time.Month, d, h int) time.Time { return time.Date(2025, m, d, h, 0, 0, 0, time.UTC) }
+	timer := func(start time.Time, mins int, project string) model.Entry {
+		return model.Entry{TS: start, Category: "timer", Project: project, StartedAt: ptr(start), EndedAt: ptr(start.Add(time.Duration(mins) * time.Minute))}
+	}
+	entries := []model.Entry{
+		timer(at(time.September, 29, 3), 60, "acme"), // Sunday night, last week
+		timer(at(time.October, 1, 9), 60, "acme"),
+		timer(at(time.October, 2, 2), 30, "acme"), // Wednesday night
+		timer(at(time.October, 2, 10), 45, "beta"),
+		{TS: at(time.October, 3, 10), Category: "note", Project: "beta"},
+		timer(at(time.October, 5, 10), 20, "beta"),
+		timer(at(time.October, 6, 2), 15, "beta"), // Sunday night
+		timer(at(time.October, 6, 5), 60, "acme"), // next week
+	}
+	start, err := ParseWeek("2025-W40", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start = start.Add(4 * time.Hour)
+	workdays := []string{"Mon", "Tue", "Wed", "Thu", "Fri"}
+	m := func(n ...int) []time.Duration {
+		var ds []time.Duration
+		for _, x := range n {
+			ds = append(ds, time.Duration(x)*time.Minute)
+		}
+		return ds
+	}
+
+	tests := []struct {
+		policy string
+		rows   []SheetRow
+		daily  []time.Duration
+		total  time.Duration
+	}{
+		{"", []SheetRow{
+			{"acme", m(0, 0, 90, 0, 0, 0), 90 * time.Minute, 90 * time.Minute},
+			{"beta", m(0, 0, 0, 45, 0, 35), 80 * time.Minute, 80 * time.Minute},
+		}, m(0, 0, 90, 45, 0, 35), 170 * time.Minute},
+		{"1h up per day", []SheetRow{
+			{"acme", m(0, 0, 120, 0, 0, 0), 2 * time.Hour, 90 * time.Minute},
+			{"beta", m(0, 0, 0, 60, 0, 60), 2 * time.Hour, 80 * time.Minute},
+		}, m(0, 0, 120, 60, 0, 60), 4 * time.Hour},
+	}
+	for _, tt := range tests {
+		r, err := rounding.Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := Timesheet(entries, start, workdays, 4*time.Hour, r, at(time.October, 10, 0))
+		var days []string
+		for _, d := range s.Days {
+			days = append(days, d.Format("Mon 02 15:04"))
+		}
+		want := []string{"Mon 29 04:00", "Tue 30 04:00", "Wed 01 04:00", "Thu 02 04:00", "Fri 03 04:00", "Sun 05 04:00"}
+		if !reflect.DeepEqual(days, want) {
+			t.Errorf("%q: columns %q, want %q", tt.policy, days, want)
+		}
+		if !reflect.DeepEqual(s.Rows, tt.rows) {
+			t.Errorf("%q: rows %v, want %v", tt.policy, s.Rows, tt.rows)
+		}
+		if !reflect.DeepEqual(s.Daily, tt.daily) || s.Total != tt.total || s.Tracked != 170*time.Minute {
+			t.Errorf("%q: daily %v, total %v, tracked %v; want %v, %v, 2h50m", tt.policy, s.Daily, s.Total, s.Tracked, tt.daily, tt.total)
+		}
+		if s.Week() != "2025-W40" {
+			t.Errorf("week %s", s.Week())
+		}
+	}
+}
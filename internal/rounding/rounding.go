@@ -0,0 +1,131 @@
+// Package rounding implements the billing rounding policies shared by
+// report, timesheet, export and invoice: round up, down or to the nearest
+// increment, either every entry on its own or each day's total.
+package rounding
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Policy rounds tracked time to an increment. The zero value keeps time as
+// tracked.
+type Policy struct {
+	Increment time.Duration
+	Mode      string // up, down or nearest
+	PerDay    bool   // round each day's total instead of each entry
+}
+
+// Parse reads "15m up", "6m nearest per day" or "30m" (up, per entry). ""
+// and "none" mean no rounding.
+func Parse(s string) (Policy, error) {
+	f := strings.Fields(strings.ToLower(s))
+	if len(f) == 0 || len(f) == 1 && f[0] == "none" {
+		return Policy{}, nil
+	}
+	inc, err := time.ParseDuration(f[0])
+	if err != nil || inc <= 0 {
+		return Policy{}, fmt.Errorf("rounding %q: want an increment, up|down|nearest and per entry|per day, e.g. \"15m up per day\"", s)
+	}
+	p := Policy{Increment: inc, Mode: "up"}
+	for i := 1; i < len(f); i++ {
+		switch w := f[i]; w {
+		case "up", "down", "nearest":
+			p.Mode = w
+		case "per":
+			if i+1 == len(f) {
+				return Policy{}, fmt.Errorf("rounding %q: per entry or per day?", s)
+			}
+			i++
+			fallthrough
+		default:
+			switch strings.TrimPrefix(f[i], "per-") {
+			case "entry":
+				p.PerDay = false
+			case "day", "daily":
+				p.PerDay = true
+			default:
+				return Policy{}, fmt.Errorf("rounding %q: unknown word %q (want up, down, nearest, per entry or per day)", s, f[i])
+			}
+		}
+	}
+	return p, nil
+}
+
+// IsZero reports whether p leaves time as tracked.
+func (p Policy) IsZero() bool { return p.Increment <= 0 }
+
+// Round applies p to d.
+func (p Policy) Round(d time.Duration) time.Duration {
+	if p.IsZero() {
+		return d
+	}
+	switch p.Mode {
+	case "down":
+		return d.Truncate(p.Increment)
+	case "nearest":
+		return d.Round(p.Increment)
+	}
+	if t := d.Truncate(p.Increment); t != d {
+		return t + p.Increment
+	}
+	return d
+}
+
+func (p Policy) String() string {
+	if p.IsZero() {
+		return "none"
+	}
+	if p.PerDay {
+		return p.Step() + " " + p.Mode + " per day"
+	}
+	return p.Step() + " " + p.Mode + " per entry"
+}
+
+// Step is the increment without zero units, e.g. "15m" or "1h".
+func (p Policy) Step() string {
+	inc := p.Increment.String() // "15m0s", "1h0m0s"
+	if strings.HasSuffix(inc, "m0s") {
+		inc = strings.TrimSuffix(inc, "0s")
+	}
+	if strings.HasSuffix(inc, "h0m") {
+		inc = strings.TrimSuffix(inc, "0m")
+	}
+	return inc
+}
+
+// Tally sums durations under a policy, keeping the tracked total alongside.
+// The zero value (no policy) rounds nothing.
+type Tally struct {
+	Policy  Policy
+	Tracked time.Duration
+
+	entries time.Duration           // per entry: the sum of rounded entries
+	days    map[int64]time.Duration // per day: tracked time by day start
+}
+
+// Add counts d, tracked on the day beginning at day.
+func (t *Tally) Add(day time.Time, d time.Duration) {
+	t.Tracked += d
+	if !t.Policy.PerDay {
+		t.entries += t.Policy.Round(d)
+		return
+	}
+	if t.days == nil {
+		t.days = map[int64]time.Duration{}
+	}
+	t.days[day.Unix()] += d
+}
+
+// Rounded is the total under the policy.
+func (t *Tally) Rounded() time.Duration {
+	if !t.Policy.PerDay {
+		return t.entries
+	}
+	var sum time.Duration
+	for _, d := range t.days {
+		sum += t.Policy.Round(d)
+	}
+	return sum
+}
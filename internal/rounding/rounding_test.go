@@ -0,0 +1,110 @@
+package rounding
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Policy
+		str  string
+	}{
+		{"", Policy{}, "none"},
+		{"none", Policy{}, "none"},
+		{"30m", Policy{Increment: 30 * time.Minute, Mode: "up"}, "30m up per entry"},
+		{"15m down", Policy{Increment: 15 * time.Minute, Mode: "down"}, "15m down per entry"},
+		{"6m Nearest per day", Policy{Increment: 6 * time.Minute, Mode: "nearest", PerDay: true}, "6m nearest per day"},
+		{"1h per-day up", Policy{Increment: time.Hour, Mode: "up", PerDay: true}, "1h up per day"},
+		{"90m daily", Policy{Increment: 90 * time.Minute, Mode: "up", PerDay: true}, "1h30m up per day"},
+		{"15m up per entry", Policy{Increment: 15 * time.Minute, Mode: "up"}, "15m up per entry"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+		if got.String() != tt.str {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got.String(), tt.str)
+		}
+		if again, err := Parse(got.String()); err != nil || again != got {
+			t.Errorf("Parse(%q) does not round-trip: %+v, %v", got.String(), again, err)
+		}
+	}
+
+	for _, in := range []string{"up", "0m", "-15m", "15m sideways", "15m per", "15m per week"} {
+		if p, err := Parse(in); err == nil {
+			t.Errorf("Parse(%q) = %+v, want an error", in, p)
+		}
+	}
+}
+
+func TestRound(t *testing.T) {
+	const m = time.Minute
+	tests := []struct {
+		mode     string
+		in, want time.Duration
+	}{
+		{"up", 0, 0},
+		{"up", 15 * m, 15 * m},
+		{"up", 15*m + time.Second, 30 * m},
+		{"down", 29 * m, 15 * m},
+		{"down", 14 * m, 0},
+		{"nearest", 22 * m, 15 * m},
+		{"nearest", 23 * m, 30 * m},
+	}
+	for _, tt := range tests {
+		p := Policy{Increment: 15 * m, Mode: tt.mode}
+		if got := p.Round(tt.in); got != tt.want {
+			t.Errorf("%s: Round(%v) = %v, want %v", tt.mode, tt.in, got, tt.want)
+		}
+	}
+	if got := (Policy{}).Round(7 * m); got != 7*m {
+		t.Errorf("zero policy rounded 7m to %v", got)
+	}
+}
+
+func TestTally(t *testing.T) {
+	mon := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)
+	tue := mon.AddDate(0, 0, 1)
+	add := []struct {
+		day time.Time
+		d   time.Duration
+	}{
+		{mon, 10 * time.Minute},
+		{mon, 10 * time.Minute},
+		{tue, 5 * time.Minute},
+	}
+	tests := []struct {
+		policy string
+		want   time.Duration
+	}{
+		{"none", 25 * time.Minute},
+		{"15m up", 45 * time.Minute},              // 15 + 15 + 15
+		{"15m up per day", 45 * time.Minute},      // 30 + 15
+		{"15m nearest per day", 15 * time.Minute}, // 15 + 0
+		{"30m up per day", 60 * time.Minute},
+		{"30m down", 0},
+	}
+	for _, tt := range tests {
+		p, err := Parse(tt.policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tally := Tally{Policy: p}
+		for _, a := range add {
+			tally.Add(a.day, a.d)
+		}
+		if tally.Tracked != 25*time.Minute {
+			t.Errorf("%s: tracked %v, want 25m", tt.policy, tally.Tracked)
+		}
+		if got := tally.Rounded(); got != tt.want {
+			t.Errorf("%s: rounded %v, want %v", tt.policy, got, tt.want)
+		}
+	}
+}
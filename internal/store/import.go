@@ -0,0 +1,44 @@
+package store
+
+func (s *SQLite) ImportEntries(recs []Import, dryRun bool) (ImportResult, error) {
+	var res ImportResult
+	tx, err := s.db.Begin()
+	if err != nil {
+		return res, err
+	}
+	defer tx.Rollback()
+
+	var ids []int64
+	seen := map[string]bool{}
+	for _, r := range recs {
+		if r.Source != "" {
+			var n int
+			if err := tx.QueryRow(`SELECT count(1) FROM entries WHERE source=?`, r.Source).Scan(&n); err != nil {
+				return res, err
+			}
+			if n > 0 || seen[r.Source] {
+				res.Duplicates++
+				continue
+			}
+			seen[r.Source] = true
+		}
+		id, err := insertEntry(tx, r.Entry)
+		if err != nil {
+			return res, err
+		}
+		if r.Source != "" {
+			if _, err := tx.Exec(`UPDATE entries SET source=? WHERE id=?`, r.Source, id); err != nil {
+				return res, err
+			}
+		}
+		ids = append(ids, id)
+		res.Added++
+	}
+	if dryRun || len(ids) == 0 {
+		return res, nil
+	}
+	if err := journalCreated(tx, "import", ids); err != nil {
+		return res, err
+	}
+	return res, tx.Commit()
+}
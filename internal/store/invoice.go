@@ -0,0 +1,51 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/ramanasai/pulse/internal/db"
+)
+
+func (s *SQLite) IssueInvoice(inv Invoice, prefix string) (Invoice, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return inv, err
+	}
+	defer tx.Rollback()
+
+	start, end := db.FormatTime(inv.Start), db.FormatTime(inv.End)
+	var issued string
+	err = tx.QueryRow(`SELECT id, number, issued_at FROM invoices WHERE project_id=? AND period_start=? AND period_end=?`,
+		inv.ProjectID, start, end).Scan(&inv.ID, &inv.Number, &issued)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		var next int64
+		if err := tx.QueryRow(`SELECT COALESCE(MAX(id),0)+1 FROM invoices`).Scan(&next); err != nil {
+			return inv, err
+		}
+		inv.Number = fmt.Sprintf("%s%04d", prefix, next)
+		res, err := tx.Exec(`INSERT INTO invoices(number, project_id, project, period_start, period_end, total, currency) VALUES(?,?,?,?,?,?,?)`,
+			inv.Number, inv.ProjectID, inv.Project, start, end, inv.Total, inv.Currency)
+		if err != nil {
+			return inv, fmt.Errorf("issuing invoice %s: %w", inv.Number, err)
+		}
+		if inv.ID, err = res.LastInsertId(); err != nil {
+			return inv, err
+		}
+		if err := tx.QueryRow(`SELECT issued_at FROM invoices WHERE id=?`, inv.ID).Scan(&issued); err != nil {
+			return inv, err
+		}
+	case err != nil:
+		return inv, err
+	default:
+		if _, err := tx.Exec(`UPDATE invoices SET project=?, total=?, currency=? WHERE id=?`, inv.Project, inv.Total, inv.Currency, inv.ID); err != nil {
+			return inv, err
+		}
+	}
+	if inv.IssuedAt, err = db.ParseTime(issued); err != nil {
+		return inv, err
+	}
+	return inv, tx.Commit()
+}
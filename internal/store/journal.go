@@ -0,0 +1,447 @@
+package store
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+// journalKeep is how many operations are kept for undo.
+const journalKeep = 200
+
+// snapshot is the journaled form of an entry. It has its own JSON names so
+// old journal rows stay readable as model.Entry evolves.
+type snapshot struct {
+	ID        int64      `json:"id"`
+	TS        time.Time  `json:"ts"`
+	Category  string     `json:"category"`
+	Text      string     `json:"text"`
+	Project   string     `json:"project,omitempty"`
+	Tags      []string   `json:"tags,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// Snapshots written before billing existed decode as billable.
+	NonBillable bool            `json:"non_billable,omitempty"`
+	Segments    []model.Segment `json:"segments,omitempty"`
+}
+
+func toSnapshot(e model.Entry) snapshot {
+	return snapshot{e.ID, e.TS, e.Category, e.Text, e.Project, e.Tags, e.StartedAt, e.EndedAt, e.DeletedAt, e.NonBillable, e.Segments}
+}
+
+func (s snapshot) entry() model.Entry {
+	return model.Entry{ID: s.ID, TS: s.TS, Category: s.Category, Text: s.Text, Project: s.Project,
+		Tags: s.Tags, StartedAt: s.StartedAt, EndedAt: s.EndedAt, DeletedAt: s.DeletedAt, NonBillable: s.NonBillable,
+		Segments: s.Segments}
+}
+
+// projectSnapshot is the journaled form of a registered project.
+type projectSnapshot struct {
+	ID            int64      `json:"id"`
+	Name          string     `json:"name"`
+	Client        string     `json:"client,omitempty"`
+	Color         string     `json:"color,omitempty"`
+	Rate          float64    `json:"rate,omitempty"`
+	Currency      string     `json:"currency,omitempty"`
+	ArchivedAt    *time.Time `json:"archived_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	BudgetSeconds int64      `json:"budget_seconds,omitempty"`
+	PeriodSeconds int64      `json:"period_budget_seconds,omitempty"`
+	BudgetPeriod  string     `json:"budget_period,omitempty"`
+}
+
+func toProjectSnapshot(p model.Project) projectSnapshot {
+	return projectSnapshot{p.ID, p.Name, p.Client, p.Color, p.Rate, p.Currency, p.ArchivedAt, p.CreatedAt,
+		int64(p.Budget / time.Second), int64(p.PeriodBudget / time.Second), p.BudgetPeriod}
+}
+
+// tagSnapshot is the journaled form of a tags row.
+type tagSnapshot struct {
+	Name string  `json:"name"`
+	Rate float64 `json:"rate,omitempty"`
+}
+
+// journal records an operation touching a single entry; before is nil when
+// the operation creates the entry.
+func journal(ex db.Execer, kind string, id int64, before *model.Entry) error {
+	if before != nil {
+		return journalEntries(ex, kind, []model.Entry{*before})
+	}
+	return journalCreated(ex, kind, []int64{id})
+}
+
+// journalCreated records one operation that created every entry in ids.
+func journalCreated(ex db.Execer, kind string, ids []int64) error {
+	opID, err := beginOp(ex, kind)
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if _, err := ex.Exec(`INSERT INTO operation_entries(operation_id, entry_id, before) VALUES(?,?,NULL)`, opID, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// journalEntries records one operation with the prior state of every entry it touches.
+func journalEntries(ex db.Execer, kind string, before []model.Entry) error {
+	opID, err := beginOp(ex, kind)
+	if err != nil {
+		return err
+	}
+	return journalEntriesOf(ex, opID, before)
+}
+
+// journalEntriesOf adds the prior state of entries to operation opID.
+func journalEntriesOf(ex db.Execer, opID int64, before []model.Entry) error {
+	for _, e := range before {
+		b, err := json.Marshal(toSnapshot(e))
+		if err != nil {
+			return err
+		}
+		if _, err := ex.Exec(`INSERT INTO operation_entries(operation_id, entry_id, before) VALUES(?,?,?)`, opID, e.ID, string(b)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// journalProject adds registered project name to operation opID, before the
+// operation changes or deletes it. Unregistered names are skipped.
+func journalProject(ex db.Execer, opID int64, name string) error {
+	p, err := getProject(ex, name)
+	if errors.Is(err, ErrProjectNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(toProjectSnapshot(p))
+	if err != nil {
+		return err
+	}
+	_, err = ex.Exec(`INSERT INTO operation_projects(operation_id, project_id, before) VALUES(?,?,?)`, opID, p.ID, string(b))
+	return err
+}
+
+// journalProjectCreated adds project id, just registered, to operation opID.
+func journalProjectCreated(ex db.Execer, opID, id int64) error {
+	_, err := ex.Exec(`INSERT INTO operation_projects(operation_id, project_id, before) VALUES(?,?,NULL)`, opID, id)
+	return err
+}
+
+// restoreProjects puts the registry rows journaled with operation opID back
+// as they were, returning their names.
+func restoreProjects(ex db.Execer, opID int64) ([]string, error) {
+	rows, err := ex.Query(`SELECT project_id, before FROM operation_projects WHERE operation_id=? ORDER BY rowid DESC`, opID)
+	if err != nil {
+		return nil, err
+	}
+	type change struct {
+		id     int64
+		before sql.NullString
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.id, &c.before); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, c := range changes {
+		if !c.before.Valid {
+			if _, err := ex.Exec(`DELETE FROM projects WHERE id=?`, c.id); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var p projectSnapshot
+		if err := json.Unmarshal([]byte(c.before.String), &p); err != nil {
+			return nil, fmt.Errorf("operation #%d: bad snapshot of project #%d: %w", opID, c.id, err)
+		}
+		if _, err := ex.Exec(`INSERT INTO projects(id, name, client, color, rate, currency, archived_at, created_at, budget, period_budget, budget_period)
+			VALUES(?, ?, NULLIF(?,''), NULLIF(?,''), NULLIF(?,0), NULLIF(?,''), ?, ?, NULLIF(?,0), NULLIF(?,0), NULLIF(?,''))
+			ON CONFLICT(id) DO UPDATE SET name=excluded.name, client=excluded.client, color=excluded.color, rate=excluded.rate,
+				currency=excluded.currency, archived_at=excluded.archived_at, created_at=excluded.created_at,
+				budget=excluded.budget, period_budget=excluded.period_budget, budget_period=excluded.budget_period`,
+			p.ID, p.Name, p.Client, p.Color, p.Rate, p.Currency, nullTime(p.ArchivedAt), db.FormatTime(p.CreatedAt),
+			p.BudgetSeconds, p.PeriodSeconds, p.BudgetPeriod); err != nil {
+			return nil, fmt.Errorf("restoring project %q: %w", p.Name, err)
+		}
+		names = append(names, p.Name)
+	}
+	return names, nil
+}
+
+// journalTag adds tag name to operation opID, before the operation changes
+// or deletes its row.
+func journalTag(ex db.Execer, opID int64, name string) error {
+	var rate float64
+	err := ex.QueryRow(`SELECT COALESCE(rate,0) FROM tags WHERE name=?`, name).Scan(&rate)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = ex.Exec(`INSERT INTO operation_tags(operation_id, name, before) VALUES(?,?,NULL)`, opID, name)
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(tagSnapshot{name, rate})
+	if err != nil {
+		return err
+	}
+	_, err = ex.Exec(`INSERT INTO operation_tags(operation_id, name, before) VALUES(?,?,?)`, opID, name, string(b))
+	return err
+}
+
+// restoreTags puts the tag rows journaled with operation opID back as they
+// were, returning their names. It runs after the entries are restored, so
+// rows the operation created are unused again and can go.
+func restoreTags(ex db.Execer, opID int64) ([]string, error) {
+	rows, err := ex.Query(`SELECT name, before FROM operation_tags WHERE operation_id=? ORDER BY rowid DESC`, opID)
+	if err != nil {
+		return nil, err
+	}
+	type change struct {
+		name   string
+		before sql.NullString
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.name, &c.before); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, c := range changes {
+		if !c.before.Valid {
+			if _, err := ex.Exec(`DELETE FROM tags WHERE name=? AND NOT EXISTS (SELECT 1 FROM entry_tags et WHERE et.tag_id = tags.id)`, c.name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		var t tagSnapshot
+		if err := json.Unmarshal([]byte(c.before.String), &t); err != nil {
+			return nil, fmt.Errorf("operation #%d: bad snapshot of tag #%s: %w", opID, c.name, err)
+		}
+		if _, err := ex.Exec(`INSERT INTO tags(name, rate) VALUES(?, NULLIF(?,0))
+			ON CONFLICT(name) DO UPDATE SET rate=excluded.rate`, t.Name, t.Rate); err != nil {
+			return nil, fmt.Errorf("restoring tag #%s: %w", t.Name, err)
+		}
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+func beginOp(ex db.Execer, kind string) (int64, error) {
+	res, err := ex.Exec(`INSERT INTO operations(kind) VALUES(?)`, kind)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	_, err = ex.Exec(`DELETE FROM operations WHERE id <= ?`, id-journalKeep)
+	return id, err
+}
+
+func (s *SQLite) Undo() (Operation, error) {
+	var op Operation
+	tx, err := s.db.Begin()
+	if err != nil {
+		return op, err
+	}
+	defer tx.Rollback()
+
+	var ts string
+	err = tx.QueryRow(`SELECT id, ts, kind FROM operations
+		WHERE undone_at IS NULL AND (EXISTS (SELECT 1 FROM operation_entries oe WHERE oe.operation_id = operations.id)
+			OR EXISTS (SELECT 1 FROM operation_projects op WHERE op.operation_id = operations.id)
+			OR EXISTS (SELECT 1 FROM operation_tags ot WHERE ot.operation_id = operations.id))
+		ORDER BY id DESC LIMIT 1`).Scan(&op.ID, &ts, &op.Kind)
+	if errors.Is(err, sql.ErrNoRows) {
+		return op, ErrNothingToUndo
+	}
+	if err != nil {
+		return op, err
+	}
+	if op.TS, err = db.ParseTime(ts); err != nil {
+		return op, err
+	}
+
+	rows, err := tx.Query(`SELECT entry_id, before FROM operation_entries WHERE operation_id=? ORDER BY rowid DESC`, op.ID)
+	if err != nil {
+		return op, err
+	}
+	type change struct {
+		id     int64
+		before sql.NullString
+	}
+	var changes []change
+	for rows.Next() {
+		var c change
+		if err := rows.Scan(&c.id, &c.before); err != nil {
+			rows.Close()
+			return op, err
+		}
+		changes = append(changes, c)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return op, err
+	}
+
+	now := db.FormatTime(time.Now())
+	for _, c := range changes {
+		op.EntryIDs = append(op.EntryIDs, c.id)
+		if !c.before.Valid {
+			// The operation created this entry: move it to the trash rather
+			// than destroying it.
+			if _, err := tx.Exec(`UPDATE entries SET deleted_at=? WHERE id=? AND deleted_at IS NULL`, now, c.id); err != nil {
+				return op, err
+			}
+			continue
+		}
+		var snap snapshot
+		if err := json.Unmarshal([]byte(c.before.String), &snap); err != nil {
+			return op, fmt.Errorf("operation #%d: bad snapshot of entry #%d: %w", op.ID, c.id, err)
+		}
+		if err := writeEntry(tx, snap.entry()); err != nil {
+			return op, err
+		}
+	}
+	if op.Projects, err = restoreProjects(tx, op.ID); err != nil {
+		return op, err
+	}
+	if op.Tags, err = restoreTags(tx, op.ID); err != nil {
+		return op, err
+	}
+	if _, err := tx.Exec(`UPDATE operations SET undone_at=? WHERE id=?`, now, op.ID); err != nil {
+		return op, err
+	}
+	return op, tx.Commit()
+}
+
+// liveEntries loads ids, failing unless every one exists and its trash state
+// matches trashed.
+func liveEntries(ex db.Execer, ids []int64, trashed bool) ([]model.Entry, error) {
+	out := make([]model.Entry, 0, len(ids))
+	for _, id := range ids {
+		e, err := getEntry(ex, id)
+		if err != nil {
+			return nil, err
+		}
+		if (e.DeletedAt != nil) != trashed {
+			if trashed {
+				return nil, fmt.Errorf("entry #%d is not in the trash", id)
+			}
+			return nil, fmt.Errorf("entry #%d is already in the trash", id)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+func (s *SQLite) DeleteEntries(ids []int64) error {
+	return s.setDeleted(ids, "rm", false)
+}
+
+func (s *SQLite) RestoreEntries(ids []int64) error {
+	return s.setDeleted(ids, "restore", true)
+}
+
+func (s *SQLite) setDeleted(ids []int64, kind string, trashed bool) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := liveEntries(tx, ids, trashed)
+	if err != nil {
+		return err
+	}
+	if err := journalEntries(tx, kind, before); err != nil {
+		return err
+	}
+	var deletedAt any
+	if !trashed {
+		deletedAt = db.FormatTime(time.Now())
+	}
+	for _, id := range ids {
+		if _, err := tx.Exec(`UPDATE entries SET deleted_at=? WHERE id=?`, deletedAt, id); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *SQLite) PurgeEntries(ids []int64) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if len(ids) == 0 {
+		rows, err := tx.Query(`SELECT id FROM entries WHERE deleted_at IS NOT NULL`)
+		if err != nil {
+			return 0, err
+		}
+		for rows.Next() {
+			var id int64
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return 0, err
+			}
+			ids = append(ids, id)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+	} else if _, err := liveEntries(tx, ids, true); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	in := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	args := make([]any, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+	// Forget journal snapshots so undo can never resurrect purged entries
+	if _, err := tx.Exec(`DELETE FROM operation_entries WHERE entry_id IN (`+in+`)`, args...); err != nil {
+		return 0, err
+	}
+	if _, err := tx.Exec(`DELETE FROM entries WHERE id IN (`+in+`)`, args...); err != nil {
+		return 0, err
+	}
+	return len(ids), tx.Commit()
+}
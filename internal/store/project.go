@@ -0,0 +1,260 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+const projectColumns = `p.id, p.name, COALESCE(p.client,''), COALESCE(p.color,''), COALESCE(p.rate,0), COALESCE(p.currency,''), p.archived_at, p.created_at,
+	COALESCE(p.budget,0), COALESCE(p.period_budget,0), COALESCE(p.budget_period,'')`
+
+func scanProject(sc scanner, extra ...any) (model.Project, error) {
+	var (
+		p              model.Project
+		archived       sql.NullString
+		created        string
+		budget, period int64
+	)
+	if err := sc.Scan(append([]any{&p.ID, &p.Name, &p.Client, &p.Color, &p.Rate, &p.Currency, &archived, &created,
+		&budget, &period, &p.BudgetPeriod}, extra...)...); err != nil {
+		return p, err
+	}
+	p.Budget, p.PeriodBudget = time.Duration(budget)*time.Second, time.Duration(period)*time.Second
+	var err error
+	if p.ArchivedAt, err = parseNullTime(archived); err != nil {
+		return p, fmt.Errorf("project %s: %w", p.Name, err)
+	}
+	if p.CreatedAt, err = db.ParseTime(created); err != nil {
+		return p, fmt.Errorf("project %s: %w", p.Name, err)
+	}
+	return p, nil
+}
+
+func getProject(ex db.Execer, name string) (model.Project, error) {
+	p, err := scanProject(ex.QueryRow(`SELECT `+projectColumns+` FROM projects p WHERE p.name=?`, name))
+	if errors.Is(err, sql.ErrNoRows) {
+		return p, fmt.Errorf("project %q: %w", name, ErrProjectNotFound)
+	}
+	return p, err
+}
+
+func (s *SQLite) GetProject(name string) (model.Project, error) {
+	return getProject(s.db, strings.TrimSpace(name))
+}
+
+func (s *SQLite) Projects() ([]ProjectUsage, error) {
+	rows, err := s.db.Query(`
+		WITH used AS (
+			SELECT e.project AS name, COUNT(*) AS n, SUM(` + db.DurationSQL + `) AS secs
+			FROM entries e WHERE e.project IS NOT NULL AND e.deleted_at IS NULL GROUP BY e.project
+		)
+		SELECT ` + projectColumns + `, 1, COALESCE(u.n,0), COALESCE(u.secs,0)
+		FROM projects p LEFT JOIN used u ON u.name = p.name
+		UNION ALL
+		SELECT 0, u.name, '', '', 0, '', NULL, '` + db.FormatTime(time.Time{}) + `', 0, 0, '', 0, u.n, u.secs
+		FROM used u WHERE u.name NOT IN (SELECT name FROM projects)
+		ORDER BY 2`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []ProjectUsage
+	for rows.Next() {
+		var u ProjectUsage
+		var secs int64
+		if u.Project, err = scanProject(rows, &u.Registered, &u.Entries, &secs); err != nil {
+			return nil, err
+		}
+		u.Duration = time.Duration(secs) * time.Second
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLite) SaveProject(p model.Project) (model.Project, error) {
+	p.Name = strings.TrimSpace(p.Name)
+	if p.Name == "" {
+		return p, fmt.Errorf("project name is empty")
+	}
+	if p.PeriodBudget <= 0 {
+		p.BudgetPeriod = ""
+	}
+	_, err := s.db.Exec(`INSERT INTO projects(name, client, color, rate, currency, budget, period_budget, budget_period)
+		VALUES(?, NULLIF(?,''), NULLIF(?,''), NULLIF(?,0), NULLIF(?,''), NULLIF(?,0), NULLIF(?,0), NULLIF(?,''))
+		ON CONFLICT(name) DO UPDATE SET client=excluded.client, color=excluded.color, rate=excluded.rate, currency=excluded.currency,
+			budget=excluded.budget, period_budget=excluded.period_budget, budget_period=excluded.budget_period`,
+		p.Name, p.Client, p.Color, p.Rate, p.Currency,
+		int64(p.Budget/time.Second), int64(p.PeriodBudget/time.Second), p.BudgetPeriod)
+	if err != nil {
+		return p, err
+	}
+	return s.GetProject(p.Name)
+}
+
+func (s *SQLite) ArchiveProject(name string, archived bool) error {
+	var at any
+	if archived {
+		at = db.FormatTime(time.Now())
+	}
+	res, err := s.db.Exec(`UPDATE projects SET archived_at=? WHERE name=?`, at, strings.TrimSpace(name))
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("project %q: %w", name, ErrProjectNotFound)
+	}
+	return nil
+}
+
+func (s *SQLite) RenameProject(from, to string) (int, error) {
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	if to == "" {
+		return 0, fmt.Errorf("new project name is empty")
+	}
+	if from == to {
+		return 0, nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if _, err := getProject(tx, to); err == nil {
+		return 0, fmt.Errorf("project %q: %w (use merge)", to, ErrProjectExists)
+	} else if !errors.Is(err, ErrProjectNotFound) {
+		return 0, err
+	}
+	opID, err := beginOp(tx, "rename")
+	if err != nil {
+		return 0, err
+	}
+	if err := journalProject(tx, opID, from); err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(`UPDATE projects SET name=? WHERE name=?`, to, from)
+	if err != nil {
+		return 0, err
+	}
+	registered, _ := res.RowsAffected()
+	n, err := moveProjects(tx, opID, []string{from}, to)
+	if err != nil {
+		return 0, err
+	}
+	if registered == 0 && n == 0 {
+		return 0, fmt.Errorf("project %q: %w", from, ErrProjectNotFound)
+	}
+	if registered == 0 {
+		res, err := tx.Exec(`INSERT INTO projects(name) VALUES(?)`, to)
+		if err != nil {
+			return 0, err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		if err := journalProjectCreated(tx, opID, id); err != nil {
+			return 0, err
+		}
+	}
+	return n, tx.Commit()
+}
+
+func (s *SQLite) MergeProjects(from []string, into string) (int, error) {
+	into = strings.TrimSpace(into)
+	if into == "" {
+		return 0, fmt.Errorf("target project name is empty")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var names []string
+	for _, f := range from {
+		f = strings.TrimSpace(f)
+		if f == into {
+			return 0, fmt.Errorf("cannot merge %q into itself", f)
+		}
+		var known int
+		if err := tx.QueryRow(`SELECT (SELECT count(1) FROM projects WHERE name=?) + (SELECT count(1) FROM entries WHERE project=?)`, f, f).Scan(&known); err != nil {
+			return 0, err
+		}
+		if known == 0 {
+			return 0, fmt.Errorf("project %q: %w", f, ErrProjectNotFound)
+		}
+		names = append(names, f)
+	}
+	opID, err := beginOp(tx, "merge")
+	if err != nil {
+		return 0, err
+	}
+	res, err := tx.Exec(`INSERT OR IGNORE INTO projects(name) VALUES(?)`, into)
+	if err != nil {
+		return 0, err
+	}
+	if created, _ := res.RowsAffected(); created > 0 {
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, err
+		}
+		if err := journalProjectCreated(tx, opID, id); err != nil {
+			return 0, err
+		}
+	}
+	n, err := moveProjects(tx, opID, names, into)
+	if err != nil {
+		return 0, err
+	}
+	for _, f := range names {
+		if err := journalProject(tx, opID, f); err != nil {
+			return 0, err
+		}
+		if _, err := tx.Exec(`DELETE FROM projects WHERE name=?`, f); err != nil {
+			return 0, err
+		}
+	}
+	return n, tx.Commit()
+}
+
+// moveProjects refiles every entry (trashed ones included) of the projects in
+// from under to, journaled with operation opID so undo puts them back.
+func moveProjects(ex db.Execer, opID int64, from []string, to string) (int, error) {
+	in := strings.TrimSuffix(strings.Repeat("?,", len(from)), ",")
+	args := make([]any, len(from))
+	for i, f := range from {
+		args[i] = f
+	}
+	rows, err := ex.Query(`SELECT `+entryColumns+` FROM entries e WHERE e.project IN (`+in+`) ORDER BY e.id`, args...)
+	if err != nil {
+		return 0, err
+	}
+	var before []model.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		before = append(before, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil || len(before) == 0 {
+		return 0, err
+	}
+	if err := journalEntriesOf(ex, opID, before); err != nil {
+		return 0, err
+	}
+	if _, err := ex.Exec(`UPDATE entries SET project=? WHERE project IN (`+in+`)`, append([]any{to}, args...)...); err != nil {
+		return 0, err
+	}
+	return len(before), nil
+}
@@ -0,0 +1,200 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+// segmentsColumn packs the segments of entry e as "start/end,start/end…",
+// oldest first, with an empty end for the running one.
+const segmentsColumn = `(SELECT group_concat(sg.started_at || '/' || COALESCE(sg.ended_at,''), ',')
+	FROM (SELECT started_at, ended_at FROM segments WHERE entry_id = e.id ORDER BY started_at) sg)`
+
+// runningTimer matches timers of alias e that have not been stopped, paused
+// or not; activeTimer only those that are not paused.
+const (
+	runningTimer = `e.category='timer' AND e.started_at IS NOT NULL AND e.ended_at IS NULL AND e.deleted_at IS NULL`
+	activeTimer  = runningTimer + ` AND (NOT EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id)
+		OR EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id AND sg.ended_at IS NULL))`
+	pausedTimer = runningTimer + ` AND EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id)
+		AND NOT EXISTS (SELECT 1 FROM segments sg WHERE sg.entry_id = e.id AND sg.ended_at IS NULL)`
+)
+
+func parseSegments(s string) ([]model.Segment, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var out []model.Segment
+	for _, part := range strings.Split(s, ",") {
+		start, end, _ := strings.Cut(part, "/")
+		var sg model.Segment
+		var err error
+		if sg.StartedAt, err = db.ParseTime(start); err != nil {
+			return nil, fmt.Errorf("segment: %w", err)
+		}
+		if end != "" {
+			t, err := db.ParseTime(end)
+			if err != nil {
+				return nil, fmt.Errorf("segment: %w", err)
+			}
+			sg.EndedAt = &t
+		}
+		out = append(out, sg)
+	}
+	return out, nil
+}
+
+// setSegments replaces the segments of entry id.
+func setSegments(ex db.Execer, id int64, segments []model.Segment) error {
+	if _, err := ex.Exec(`DELETE FROM segments WHERE entry_id=?`, id); err != nil {
+		return err
+	}
+	for _, sg := range segments {
+		if _, err := ex.Exec(`INSERT INTO segments(entry_id, started_at, ended_at) VALUES(?,?,?)`,
+			id, db.FormatTime(sg.StartedAt), nullTime(sg.EndedAt)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reviseSegments replaces the segments of entry id like setSegments, first
+// recording the entry in entry_revisions when they change. The
+// entries_revision trigger only sees the entries row, which a pause or resume
+// leaves alone.
+func reviseSegments(ex db.Execer, id int64, segments []model.Segment) error {
+	var old sql.NullString
+	if err := ex.QueryRow(`SELECT `+segmentsColumn+` FROM entries e WHERE e.id=?`, id).Scan(&old); err != nil {
+		return err
+	}
+	if old.String == packSegments(segments) {
+		return nil
+	}
+	if _, err := ex.Exec(`INSERT INTO entry_revisions(entry_id, ts, category, text, project, tags, started_at, ended_at, deleted_at, billable, segments)
+		SELECT e.id, e.ts, e.category, e.text, e.project, e.tags, e.started_at, e.ended_at, e.deleted_at, e.billable, `+segmentsColumn+`
+		FROM entries e WHERE e.id=?`, id); err != nil {
+		return err
+	}
+	return setSegments(ex, id, segments)
+}
+
+// packSegments renders segments the way segmentsColumn does.
+func packSegments(segments []model.Segment) string {
+	parts := make([]string, len(segments))
+	for i, sg := range segments {
+		parts[i] = db.FormatTime(sg.StartedAt) + "/"
+		if sg.EndedAt != nil {
+			parts[i] += db.FormatTime(*sg.EndedAt)
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// revisions counts the recorded revisions of entry id.
+func revisions(ex db.Execer, id int64) (int, error) {
+	var n int
+	err := ex.QueryRow(`SELECT count(1) FROM entry_revisions WHERE entry_id=?`, id).Scan(&n)
+	return n, err
+}
+
+// findTimer loads running timer id, or the most recently started timer
+// matching cond when id is 0.
+func findTimer(ex db.Execer, id int64, cond string) (model.Entry, error) {
+	if id > 0 {
+		e, err := scanEntry(ex.QueryRow(`SELECT `+entryColumns+` FROM entries e WHERE e.id=? AND e.category='timer' AND e.deleted_at IS NULL`, id))
+		if errors.Is(err, sql.ErrNoRows) {
+			return e, fmt.Errorf("timer #%d: %w", id, ErrNotFound)
+		}
+		if err == nil && !e.Running() {
+			return e, fmt.Errorf("timer #%d: %w", id, ErrNotRunning)
+		}
+		return e, err
+	}
+	e, err := scanEntry(ex.QueryRow(`SELECT ` + entryColumns + ` FROM entries e WHERE ` + cond + ` ORDER BY e.started_at DESC LIMIT 1`))
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, ErrNoTimers
+	}
+	return e, err
+}
+
+func (s *SQLite) PauseTimer(id int64) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return model.Entry{}, err
+	}
+	defer tx.Rollback()
+
+	e, err := findTimer(tx, id, activeTimer)
+	if err != nil {
+		return e, err
+	}
+	if e.Paused() {
+		return e, fmt.Errorf("timer #%d: %w", e.ID, ErrPaused)
+	}
+	if err := journal(tx, "pause", e.ID, &e); err != nil {
+		return e, err
+	}
+	segments := e.Segments
+	if len(segments) == 0 {
+		segments = []model.Segment{{StartedAt: *e.StartedAt}}
+	}
+	last := &segments[len(segments)-1]
+	now := time.Now()
+	if now.Before(last.StartedAt) {
+		now = last.StartedAt
+	}
+	last.EndedAt = &now
+	if err := reviseSegments(tx, e.ID, segments); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(e.ID)
+}
+
+func (s *SQLite) ResumeTimer(id int64, allowMultiple bool) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return model.Entry{}, err
+	}
+	defer tx.Rollback()
+
+	e, err := findTimer(tx, id, pausedTimer)
+	if err != nil {
+		return e, err
+	}
+	if !e.Paused() {
+		return e, fmt.Errorf("timer #%d: %w", e.ID, ErrNotPaused)
+	}
+	if !allowMultiple {
+		var n int
+		if err := tx.QueryRow(`SELECT count(1) FROM entries e WHERE ` + activeTimer).Scan(&n); err != nil {
+			return e, err
+		}
+		if n > 0 {
+			return e, ErrTimerRunning
+		}
+	}
+	if err := journal(tx, "resume", e.ID, &e); err != nil {
+		return e, err
+	}
+	now := time.Now()
+	if last := e.Segments[len(e.Segments)-1]; now.Before(*last.EndedAt) {
+		now = *last.EndedAt
+	}
+	segments := append(e.Segments[:len(e.Segments):len(e.Segments)], model.Segment{StartedAt: now})
+	if err := reviseSegments(tx, e.ID, segments); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(e.ID)
+}
@@ -0,0 +1,445 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+// SQLite implements Store on top of the migrated pulse database.
+type SQLite struct {
+	db *sql.DB
+}
+
+var _ Store = (*SQLite)(nil)
+
+// Open opens (and migrates) the database at path.
+func Open(path string) (*SQLite, error) {
+	dbh, err := db.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	return New(dbh), nil
+}
+
+// New wraps an already migrated database handle.
+func New(dbh *sql.DB) *SQLite { return &SQLite{db: dbh} }
+
+// DB exposes the underlying handle for maintenance commands.
+func (s *SQLite) DB() *sql.DB { return s.db }
+
+func (s *SQLite) Close() error { return s.db.Close() }
+
+const entryColumns = `e.id, e.ts, e.category, COALESCE(e.project,''), COALESCE(e.tags,''), e.text, e.started_at, e.ended_at, e.deleted_at, COALESCE(e.billable,1),
+	` + segmentsColumn
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEntry(sc scanner, extra ...any) (model.Entry, error) {
+	var (
+		e              model.Entry
+		ts, tags       string
+		started, ended sql.NullString
+		deleted        sql.NullString
+		billable       bool
+		segments       sql.NullString
+	)
+	dest := append([]any{&e.ID, &ts, &e.Category, &e.Project, &tags, &e.Text, &started, &ended, &deleted, &billable, &segments}, extra...)
+	if err := sc.Scan(dest...); err != nil {
+		return e, err
+	}
+	var err error
+	if e.TS, err = db.ParseTime(ts); err != nil {
+		return e, fmt.Errorf("entry #%d: %w", e.ID, err)
+	}
+	e.Tags = db.ParseTags(tags)
+	e.NonBillable = !billable
+	if e.StartedAt, err = parseNullTime(started); err != nil {
+		return e, fmt.Errorf("entry #%d: %w", e.ID, err)
+	}
+	if e.EndedAt, err = parseNullTime(ended); err != nil {
+		return e, fmt.Errorf("entry #%d: %w", e.ID, err)
+	}
+	if e.DeletedAt, err = parseNullTime(deleted); err != nil {
+		return e, fmt.Errorf("entry #%d: %w", e.ID, err)
+	}
+	if e.Segments, err = parseSegments(segments.String); err != nil {
+		return e, fmt.Errorf("entry #%d: %w", e.ID, err)
+	}
+	return e, nil
+}
+
+func parseNullTime(ns sql.NullString) (*time.Time, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	t, err := db.ParseTime(ns.String)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func nullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return db.FormatTime(*t)
+}
+
+// where renders f as SQL conditions over the entries alias e.
+func (f Filter) where() (string, []any) {
+	conds := []string{"e.deleted_at IS NULL"}
+	if f.Trashed {
+		conds[0] = "e.deleted_at IS NOT NULL"
+	}
+	var args []any
+	if !f.Since.IsZero() {
+		conds = append(conds, "e.ts >= ?")
+		args = append(args, db.FormatTime(f.Since))
+	}
+	if !f.Until.IsZero() {
+		conds = append(conds, "e.ts < ?")
+		args = append(args, db.FormatTime(f.Until))
+	}
+	if p := strings.TrimSpace(f.Project); p != "" {
+		conds = append(conds, "e.project = ?")
+		args = append(args, p)
+	}
+	if c := strings.TrimSpace(f.Category); c != "" {
+		conds = append(conds, "e.category = ?")
+		args = append(args, c)
+	}
+	if cond, condArgs := db.TagCondition("e.id", f.Tags, f.AnyTag); cond != "" {
+		conds = append(conds, cond)
+		args = append(args, condArgs...)
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (f Filter) limit() int {
+	if f.Limit == NoLimit {
+		return -1 // SQLite: no limit
+	}
+	if f.Limit <= 0 || f.Limit > 1000 {
+		return 200
+	}
+	return f.Limit
+}
+
+func (s *SQLite) CreateEntry(e model.Entry) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return e, err
+	}
+	defer tx.Rollback()
+	id, err := insertEntry(tx, e)
+	if err != nil {
+		return e, err
+	}
+	if err := journal(tx, "log", id, nil); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(id)
+}
+
+func insertEntry(ex db.Execer, e model.Entry) (int64, error) {
+	if e.TS.IsZero() {
+		e.TS = time.Now()
+	}
+	if e.Category == "" {
+		e.Category = "note"
+	}
+	res, err := ex.Exec(`INSERT INTO entries(ts, category, text, project, tags, started_at, ended_at, billable) VALUES(?,?,?,NULLIF(?,''),NULLIF(?,''),?,?,?)`,
+		db.FormatTime(e.TS), e.Category, e.Text, e.Project, db.JoinTags(e.Tags), nullTime(e.StartedAt), nullTime(e.EndedAt), !e.NonBillable)
+	if err != nil {
+		return 0, err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+	if err := setSegments(ex, id, e.Segments); err != nil {
+		return 0, err
+	}
+	return id, db.SetEntryTags(ex, id, e.Tags)
+}
+
+// writeEntry overwrites every column of row e.ID, inserting the row if it no
+// longer exists.
+func writeEntry(ex db.Execer, e model.Entry) error {
+	revised, err := revisions(ex, e.ID)
+	if err != nil {
+		return err
+	}
+	args := []any{db.FormatTime(e.TS), e.Category, e.Text, e.Project, db.JoinTags(e.Tags),
+		nullTime(e.StartedAt), nullTime(e.EndedAt), nullTime(e.DeletedAt), !e.NonBillable, e.ID}
+	res, err := ex.Exec(`UPDATE entries SET ts=?, category=?, text=?, project=NULLIF(?,''), tags=NULLIF(?,''),
+		started_at=?, ended_at=?, deleted_at=?, billable=? WHERE id=?`, args...)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if _, err := ex.Exec(`INSERT INTO entries(ts, category, text, project, tags, started_at, ended_at, deleted_at, billable, id)
+			VALUES(?,?,?,NULLIF(?,''),NULLIF(?,''),?,?,?,?,?)`, args...); err != nil {
+			return err
+		}
+	} else if now, err := revisions(ex, e.ID); err != nil {
+		return err
+	} else if now == revised {
+		// The trigger saw no change, but the segments may still have one
+		if err := reviseSegments(ex, e.ID, e.Segments); err != nil {
+			return err
+		}
+	}
+	if err := setSegments(ex, e.ID, e.Segments); err != nil {
+		return err
+	}
+	return db.SetEntryTags(ex, e.ID, e.Tags)
+}
+
+func getEntry(ex db.Execer, id int64) (model.Entry, error) {
+	e, err := scanEntry(ex.QueryRow(`SELECT `+entryColumns+` FROM entries e WHERE e.id=?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return e, fmt.Errorf("entry #%d: %w", id, ErrNotFound)
+	}
+	return e, err
+}
+
+func (s *SQLite) GetEntry(id int64) (model.Entry, error) {
+	return getEntry(s.db, id)
+}
+
+func (s *SQLite) UpdateEntry(e model.Entry) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return e, err
+	}
+	defer tx.Rollback()
+
+	before, err := getEntry(tx, e.ID)
+	if err != nil {
+		return e, err
+	}
+	if err := journal(tx, "edit", e.ID, &before); err != nil {
+		return e, err
+	}
+	e.DeletedAt = before.DeletedAt
+	if err := writeEntry(tx, e); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(e.ID)
+}
+
+func (s *SQLite) History(id int64) ([]Revision, error) {
+	current, err := s.GetEntry(id)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.db.Query(`
+		SELECT e.entry_id, e.ts, e.category, COALESCE(e.project,''), COALESCE(e.tags,''), e.text,
+			e.started_at, e.ended_at, e.deleted_at, COALESCE(e.billable,1), e.segments, e.changed_at
+		FROM entry_revisions e
+		WHERE e.entry_id=?
+		ORDER BY e.id ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Revision
+	for rows.Next() {
+		var changed string
+		before, err := scanEntry(rows, &changed)
+		if err != nil {
+			return nil, err
+		}
+		r := Revision{Before: before}
+		if r.ChangedAt, err = db.ParseTime(changed); err != nil {
+			return nil, err
+		}
+		if n := len(out); n > 0 {
+			out[n-1].After = before
+		}
+		out = append(out, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if n := len(out); n > 0 {
+		out[n-1].After = current
+	}
+	return out, nil
+}
+
+func (s *SQLite) ListEntries(f Filter) ([]model.Entry, error) {
+	where, args := f.where()
+	rows, err := s.db.Query(`SELECT `+entryColumns+` FROM entries e WHERE `+where+` ORDER BY e.ts DESC LIMIT ?`, append(args, f.limit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []model.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLite) StartTimer(e model.Entry, allowMultiple bool) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return e, err
+	}
+	defer tx.Rollback()
+
+	if !allowMultiple {
+		var n int
+		if err := tx.QueryRow(`SELECT count(1) FROM entries e WHERE ` + activeTimer).Scan(&n); err != nil {
+			return e, err
+		}
+		if n > 0 {
+			return e, ErrTimerRunning
+		}
+	}
+
+	now := time.Now()
+	if e.TS.IsZero() {
+		e.TS = now
+	}
+	if e.StartedAt == nil {
+		e.StartedAt = &e.TS
+	}
+	e.Category = "timer"
+	e.EndedAt = nil
+	id, err := insertEntry(tx, e)
+	if err != nil {
+		return e, err
+	}
+	if err := journal(tx, "start", id, nil); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(id)
+}
+
+func (s *SQLite) StopTimer(id int64, note string) (model.Entry, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return model.Entry{}, err
+	}
+	defer tx.Rollback()
+
+	e, err := findTimer(tx, id, runningTimer)
+	if err != nil {
+		return e, err
+	}
+
+	end := time.Now()
+	if e.Paused() {
+		end = *e.Segments[len(e.Segments)-1].EndedAt
+	}
+	if end.Before(*e.StartedAt) {
+		end = *e.StartedAt
+	}
+	text := e.Text
+	if strings.TrimSpace(note) != "" {
+		sep := "\n"
+		if strings.Contains(text, "\n") {
+			sep = "\n\n"
+		}
+		text = text + sep + "Stop note: " + note
+	}
+
+	if err := journal(tx, "stop", e.ID, &e); err != nil {
+		return e, err
+	}
+	if _, err := tx.Exec(`UPDATE entries SET ended_at=?, text=? WHERE id=?`, db.FormatTime(end), text, e.ID); err != nil {
+		return e, err
+	}
+	if _, err := tx.Exec(`UPDATE segments SET ended_at=? WHERE entry_id=? AND ended_at IS NULL`, db.FormatTime(end), e.ID); err != nil {
+		return e, err
+	}
+	if err := tx.Commit(); err != nil {
+		return e, err
+	}
+	return s.GetEntry(e.ID)
+}
+
+func (s *SQLite) Search(query string, f Filter) ([]SearchResult, error) {
+	where, args := f.where()
+	q := `
+		SELECT ` + entryColumns + `,
+			snippet(entries_fts, 0, '[', ']', '…', 8) AS snip,
+			bm25(entries_fts) AS rank
+		FROM entries_fts
+		JOIN entries e ON e.id = entries_fts.rowid
+		WHERE entries_fts MATCH ? AND ` + where + `
+		ORDER BY rank ASC, e.ts DESC
+		LIMIT ?`
+	args = append([]any{query}, args...)
+	rows, err := s.db.Query(q, append(args, f.limit())...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []SearchResult
+	for rows.Next() {
+		var r SearchResult
+		var snip sql.NullString
+		r.Entry, err = scanEntry(rows, &snip, &r.Rank)
+		if err != nil {
+			return nil, err
+		}
+		r.Snippet = snip.String
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLite) Summarize(f Filter) ([]CategoryTotal, error) {
+	where, args := f.where()
+	rows, err := s.db.Query(`
+		SELECT e.category, COUNT(*), COALESCE(SUM(`+db.DurationSQL+`),0)
+		FROM entries e
+		WHERE `+where+`
+		GROUP BY e.category
+		ORDER BY e.category ASC`, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []CategoryTotal
+	for rows.Next() {
+		var t CategoryTotal
+		var secs int64
+		if err := rows.Scan(&t.Category, &t.Count, &secs); err != nil {
+			return nil, err
+		}
+		t.Duration = time.Duration(secs) * time.Second
+		out = append(out, t)
+	}
+	return out, rows.Err()
+}
@@ -0,0 +1,188 @@
+// Package store is the typed data access layer shared by the CLI and the TUI.
+package store
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+var (
+	ErrNotFound      = errors.New("entry not found")
+	ErrTimerRunning  = errors.New("an active timer already exists")
+	ErrNotRunning    = errors.New("timer is not active")
+	ErrNoTimers      = errors.New("no active timers")
+	ErrPaused        = errors.New("timer is paused")
+	ErrNotPaused     = errors.New("timer is not paused")
+	ErrNothingToUndo = errors.New("nothing to undo")
+
+	ErrProjectNotFound = errors.New("project not found")
+	ErrProjectExists   = errors.New("project already exists")
+	ErrTagNotFound     = errors.New("tag not found")
+	ErrTagExists       = errors.New("tag already exists")
+)
+
+// Filter narrows ListEntries, Search and Summarize. Zero values mean "no constraint".
+type Filter struct {
+	Since    time.Time
+	Until    time.Time
+	Project  string
+	Category string
+	Tags     []string
+	AnyTag   bool // match entries with any of Tags instead of all
+	Trashed  bool // only entries in the trash; otherwise trashed entries are excluded
+	Limit    int  // default 200, at most 1000; NoLimit for every match
+}
+
+// NoLimit as Filter.Limit returns every matching entry (for exports).
+const NoLimit = -1
+
+// SearchResult is an entry matched by full-text search.
+type SearchResult struct {
+	model.Entry
+	Snippet string // matched terms wrapped in [ ]
+	Rank    float64
+}
+
+// CategoryTotal is one row of a Summarize result.
+type CategoryTotal struct {
+	Category string
+	Count    int
+	Duration time.Duration
+}
+
+// Revision is one recorded change to an entry: its values before and after.
+type Revision struct {
+	ChangedAt time.Time
+	Before    model.Entry
+	After     model.Entry
+}
+
+// Operation is one journaled mutation, as reverted by Undo.
+type Operation struct {
+	ID       int64
+	TS       time.Time
+	Kind     string // log, start, stop, pause, resume, edit, rm, restore, ...
+	EntryIDs []int64
+	Projects []string // registered projects put back, for rename and merge
+	Tags     []string // tag rows put back, for tags rename, merge and delete
+}
+
+// Import is an entry read from another tool. Source fingerprints the
+// original record; entries whose Source already exists are skipped.
+type Import struct {
+	model.Entry
+	Source string
+}
+
+// ImportResult counts what ImportEntries did (or would do, on a dry run).
+type ImportResult struct {
+	Added      int
+	Duplicates int
+}
+
+// ProjectUsage is a project with the entries filed under it. Names used by
+// entries but never registered are listed too, with Registered false.
+type ProjectUsage struct {
+	model.Project
+	Registered bool
+	Entries    int
+	Duration   time.Duration
+}
+
+// TagUsage is a tag with how many entries (outside the trash) carry it.
+type TagUsage struct {
+	Name     string
+	Entries  int
+	LastUsed time.Time
+	Rate     float64 // hourly rate overriding the project's; 0 when unset
+}
+
+// Invoice is an issued invoice number and what it was issued for.
+type Invoice struct {
+	ID        int64
+	Number    string
+	ProjectID int64 // the registered project; renaming it keeps the invoice
+	Project   string
+	Start     time.Time
+	End       time.Time
+	Total     float64
+	Currency  string
+	IssuedAt  time.Time
+}
+
+type Store interface {
+	CreateEntry(e model.Entry) (model.Entry, error)
+	GetEntry(id int64) (model.Entry, error)
+	// UpdateEntry overwrites every field of the entry with id e.ID.
+	UpdateEntry(e model.Entry) (model.Entry, error)
+	ListEntries(f Filter) ([]model.Entry, error)
+	// StartTimer inserts a running timer; unless allowMultiple it fails with
+	// ErrTimerRunning when another timer is active.
+	StartTimer(e model.Entry, allowMultiple bool) (model.Entry, error)
+	// StopTimer ends timer id, or the most recently started one when id is 0,
+	// appending note to its text when non-empty. A paused timer ends when it
+	// was paused.
+	StopTimer(id int64, note string) (model.Entry, error)
+	// PauseTimer ends the running segment of timer id, or of the most
+	// recently started active timer when id is 0. Paused timers do not
+	// count against StartTimer's single active timer.
+	PauseTimer(id int64) (model.Entry, error)
+	// ResumeTimer starts a new segment on paused timer id, or the most
+	// recently started paused one when id is 0; unless allowMultiple it fails
+	// with ErrTimerRunning when another timer is active.
+	ResumeTimer(id int64, allowMultiple bool) (model.Entry, error)
+	// DeleteEntries moves entries to the trash.
+	DeleteEntries(ids []int64) error
+	// RestoreEntries takes entries back out of the trash.
+	RestoreEntries(ids []int64) error
+	// PurgeEntries permanently removes trashed entries (all of them when ids
+	// is empty) and returns how many were removed. It cannot be undone.
+	PurgeEntries(ids []int64) (int, error)
+	// Undo reverts the most recent operation that has not been undone yet.
+	Undo() (Operation, error)
+	// History returns the revisions of an entry, oldest first.
+	History(id int64) ([]Revision, error)
+	// ImportEntries inserts recs in one transaction, journaled as a single
+	// operation. With dryRun nothing is written.
+	ImportEntries(recs []Import, dryRun bool) (ImportResult, error)
+	// Projects lists registered projects and unregistered names in use, by name.
+	Projects() ([]ProjectUsage, error)
+	// GetProject returns the registered project name, or ErrProjectNotFound.
+	GetProject(name string) (model.Project, error)
+	// SaveProject registers p, or overwrites the client, color, rate and
+	// currency of the project already registered as p.Name.
+	SaveProject(p model.Project) (model.Project, error)
+	// RenameProject renames a project (registered or merely in use) and moves
+	// its entries, returning how many moved. Renaming onto a registered
+	// project fails with ErrProjectExists; merge instead.
+	RenameProject(from, to string) (int, error)
+	// MergeProjects moves the entries of every project in from into into,
+	// registering into if needed, and unregisters the others.
+	MergeProjects(from []string, into string) (int, error)
+	// ArchiveProject archives or unarchives a registered project.
+	ArchiveProject(name string, archived bool) error
+	// Tags lists tags in use, most used first.
+	Tags() ([]TagUsage, error)
+	// RenameTag renames a tag on every entry, failing with ErrTagExists when
+	// to is already in use; MergeTags folds from into into and DeleteTags
+	// removes tags from every entry. Each rewrites the affected entries,
+	// trashed ones included, as one journaled operation and returns how many
+	// changed.
+	RenameTag(from, to string) (int, error)
+	MergeTags(from []string, into string) (int, error)
+	DeleteTags(names []string) (int, error)
+	// SetTagRate sets the hourly rate of a tag in use; 0 clears it.
+	SetTagRate(name string, rate float64) error
+	// TagRates maps every tag with a rate to that rate.
+	TagRates() (map[string]float64, error)
+	// IssueInvoice records inv under the next running number (prefix plus
+	// four digits). An invoice already issued for the same project (by id, so
+	// across renames) and period keeps its number and gets the new name and
+	// total.
+	IssueInvoice(inv Invoice, prefix string) (Invoice, error)
+	Search(query string, f Filter) ([]SearchResult, error)
+	Summarize(f Filter) ([]CategoryTotal, error)
+	Close() error
+}
@@ -0,0 +1,683 @@
+package store
+
+import (
+	"errors"
+	"maps"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+func newTestStore(t *testing.T) *SQLite {
+	t.Helper()
+	st, err := Open(filepath.Join(t.TempDir(), "pulse.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { st.Close() })
+	return st
+}
+
+// ago is now minus d at the database's millisecond precision.
+func ago(d time.Duration) time.Time {
+	return time.Now().Add(-d).Truncate(time.Millisecond)
+}
+
+func ptr(t time.Time) *time.Time { return &t }
+
+func mustCreate(t *testing.T, st *SQLite, e model.Entry) model.Entry {
+	t.Helper()
+	e, err := st.CreateEntry(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func ids(entries []model.Entry) []int64 {
+	out := make([]int64, len(entries))
+	for i, e := range entries {
+		out[i] = e.ID
+	}
+	slices.Sort(out)
+	return out
+}
+
+func TestEntryRoundTrip(t *testing.T) {
+	st := newTestStore(t)
+	start := ago(2 * time.Hour)
+	want := model.Entry{
+		TS: start, Category: "task", Text: "fix login", Project: "acme", Tags: []string{"bug", "prod"},
+		StartedAt: ptr(start), EndedAt: ptr(start.Add(90 * time.Minute)),
+	}
+	got := mustCreate(t, st, want)
+	if got.ID == 0 || !got.TS.Equal(want.TS) || got.Text != want.Text || got.Project != want.Project ||
+		!slices.Equal(got.Tags, want.Tags) || !got.StartedAt.Equal(start) || !got.EndedAt.Equal(*want.EndedAt) {
+		t.Fatalf("CreateEntry = %+v", got)
+	}
+	if d := got.Duration(time.Now()); d != 90*time.Minute {
+		t.Errorf("duration = %v", d)
+	}
+
+	got.Text, got.Tags = "fixed login", []string{"bug"}
+	if _, err := st.UpdateEntry(got); err != nil {
+		t.Fatal(err)
+	}
+	again, err := st.GetEntry(got.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Text != "fixed login" || !slices.Equal(again.Tags, []string{"bug"}) {
+		t.Errorf("after UpdateEntry = %+v", again)
+	}
+	if _, err := st.GetEntry(999); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetEntry(999) = %v, want ErrNotFound", err)
+	}
+}
+
+func TestListEntriesFilter(t *testing.T) {
+	st := newTestStore(t)
+	a := mustCreate(t, st, model.Entry{TS: ago(3 * time.Hour), Category: "note", Text: "a", Project: "acme", Tags: []string{"bug"}})
+	b := mustCreate(t, st, model.Entry{TS: ago(2 * time.Hour), Category: "task", Text: "b", Tags: []string{"bug", "prod"}})
+	c := mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "task", Text: "c", Project: "acme", Tags: []string{"prod"}})
+	d := mustCreate(t, st, model.Entry{TS: ago(time.Minute), Category: "task", Text: "d", Tags: []string{"bug"}})
+	if err := st.DeleteEntries([]int64{d.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		f    Filter
+		want []int64
+	}{
+		{"all", Filter{}, []int64{a.ID, b.ID, c.ID}},
+		{"project", Filter{Project: "acme"}, []int64{a.ID, c.ID}},
+		{"category", Filter{Category: "task"}, []int64{b.ID, c.ID}},
+		{"tag", Filter{Tags: []string{"bug"}}, []int64{a.ID, b.ID}},
+		{"all tags", Filter{Tags: []string{"bug", "prod"}}, []int64{b.ID}},
+		{"any tag", Filter{Tags: []string{"prod", "nope"}, AnyTag: true}, []int64{b.ID, c.ID}},
+		{"since", Filter{Since: ago(150 * time.Minute)}, []int64{b.ID, c.ID}},
+		{"until", Filter{Until: ago(150 * time.Minute)}, []int64{a.ID}},
+		{"trash", Filter{Trashed: true}, []int64{d.ID}},
+		{"limit", Filter{Limit: 1}, []int64{c.ID}},
+	}
+	for _, tt := range tests {
+		got, err := st.ListEntries(tt.f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if !slices.Equal(ids(got), tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, ids(got), tt.want)
+		}
+	}
+}
+
+func TestUndo(t *testing.T) {
+	st := newTestStore(t)
+	if _, err := st.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("Undo on an empty journal = %v", err)
+	}
+
+	e := mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "first", Tags: []string{"a"}})
+	edited := e
+	edited.Text, edited.Tags = "second", []string{"b"}
+	if _, err := st.UpdateEntry(edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.DeleteEntries([]int64{e.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		kind    string
+		check   func(model.Entry) bool
+		explain string
+	}{
+		{"rm", func(e model.Entry) bool { return e.DeletedAt == nil && e.Text == "second" }, "back out of the trash"},
+		{"edit", func(e model.Entry) bool { return e.Text == "first" && slices.Equal(e.Tags, []string{"a"}) }, "first text and tags"},
+		{"log", func(e model.Entry) bool { return e.DeletedAt != nil }, "in the trash"},
+	}
+	for _, s := range steps {
+		op, err := st.Undo()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op.Kind != s.kind || !slices.Equal(op.EntryIDs, []int64{e.ID}) {
+			t.Errorf("Undo = %s of %v, want %s of #%d", op.Kind, op.EntryIDs, s.kind, e.ID)
+		}
+		got, err := st.GetEntry(e.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.check(got) {
+			t.Errorf("after undoing %s the entry should be %s: %+v", s.kind, s.explain, got)
+		}
+	}
+	if _, err := st.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("Undo after undoing everything = %v", err)
+	}
+}
+
+func TestUndoTimer(t *testing.T) {
+	st := newTestStore(t)
+	e, err := st.StartTimer(model.Entry{TS: ago(time.Hour), Text: "work"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.StopTimer(e.ID, ""); err != nil {
+		t.Fatal(err)
+	}
+	if op, err := st.Undo(); err != nil || op.Kind != "stop" {
+		t.Fatalf("Undo = %+v, %v", op, err)
+	}
+	if got, err := st.GetEntry(e.ID); err != nil || !got.Running() {
+		t.Errorf("after undoing stop = %+v, %v", got, err)
+	}
+	if op, err := st.Undo(); err != nil || op.Kind != "start" {
+		t.Fatalf("Undo = %+v, %v", op, err)
+	}
+	if got, err := st.GetEntry(e.ID); err != nil || got.DeletedAt == nil {
+		t.Errorf("after undoing start = %+v, %v", got, err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	st := newTestStore(t)
+	e := mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "one"})
+	for _, text := range []string{"two", "three"} {
+		e.Text = text
+		if _, err := st.UpdateEntry(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	revs, err := st.History(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 2 || revs[0].Before.Text != "one" || revs[0].After.Text != "two" || revs[1].After.Text != "three" {
+		t.Errorf("History = %+v", revs)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	st := newTestStore(t)
+	first, err := st.StartTimer(model.Entry{TS: ago(30 * time.Minute), Text: "first"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !first.Running() || first.Category != "timer" {
+		t.Errorf("StartTimer = %+v", first)
+	}
+	if _, err := st.StartTimer(model.Entry{Text: "second"}, false); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("second StartTimer = %v, want ErrTimerRunning", err)
+	}
+	stopped, err := st.StopTimer(0, "done")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stopped.ID != first.ID || stopped.Running() || stopped.Text != "first\nStop note: done" {
+		t.Errorf("StopTimer = %+v", stopped)
+	}
+	if _, err := st.StopTimer(0, ""); !errors.Is(err, ErrNoTimers) {
+		t.Errorf("StopTimer without timers = %v, want ErrNoTimers", err)
+	}
+	if _, err := st.StopTimer(first.ID, ""); !errors.Is(err, ErrNotRunning) {
+		t.Errorf("StopTimer of a stopped timer = %v, want ErrNotRunning", err)
+	}
+
+	// SQL measures the timer the same way
+	d := stopped.Duration(time.Now())
+	totals, err := st.Summarize(Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(totals) != 1 || totals[0].Count != 1 || totals[0].Duration < d-time.Second || totals[0].Duration > d+time.Second {
+		t.Errorf("Summarize = %+v, want one timer of about %v", totals, d)
+	}
+}
+
+// pausedEntry starts a timer an hour ago that worked its first 20 minutes and
+// has been paused since.
+func pausedEntry(t *testing.T, st *SQLite) model.Entry {
+	t.Helper()
+	start := ago(time.Hour)
+	e, err := st.StartTimer(model.Entry{TS: start, Text: "paused"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Segments = []model.Segment{{StartedAt: start, EndedAt: ptr(start.Add(20 * time.Minute))}}
+	if e, err = st.UpdateEntry(e); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Paused() {
+		t.Fatalf("timer is not paused: %+v", e)
+	}
+	return e
+}
+
+func TestPauseResume(t *testing.T) {
+	st := newTestStore(t)
+	paused := pausedEntry(t, st)
+	if _, err := st.PauseTimer(paused.ID); !errors.Is(err, ErrPaused) {
+		t.Errorf("PauseTimer of a paused timer = %v, want ErrPaused", err)
+	}
+
+	// A paused timer does not block a new one, which then blocks resuming
+	other, err := st.StartTimer(model.Entry{Text: "other"}, false)
+	if err != nil {
+		t.Fatalf("StartTimer next to a paused timer: %v", err)
+	}
+	if _, err := st.ResumeTimer(paused.ID, false); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("ResumeTimer with another active timer = %v, want ErrTimerRunning", err)
+	}
+	if _, err := st.ResumeTimer(other.ID, false); !errors.Is(err, ErrNotPaused) {
+		t.Errorf("ResumeTimer of a running timer = %v, want ErrNotPaused", err)
+	}
+	if e, err := st.PauseTimer(0); err != nil || e.ID != other.ID || !e.Paused() {
+		t.Fatalf("PauseTimer(0) = %+v, %v", e, err)
+	}
+
+	resumed, err := st.ResumeTimer(paused.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resumed.Paused() || len(resumed.Segments) != 2 || resumed.Segments[1].EndedAt != nil {
+		t.Fatalf("ResumeTimer = %+v", resumed)
+	}
+	stopped, err := st.StopTimer(paused.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := stopped.Duration(time.Now())
+	if d < 20*time.Minute || d > 21*time.Minute {
+		t.Errorf("stopped duration = %v, want 20m plus the moment since resuming", d)
+	}
+
+	// SQL sums the segments the same way
+	totals, err := st.Summarize(Filter{Category: "timer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := d + other.Duration(time.Now())
+	if len(totals) != 1 || totals[0].Duration < want-time.Second || totals[0].Duration > want+time.Second {
+		t.Errorf("Summarize = %+v, want about %v", totals, want)
+	}
+
+	// A paused timer stops when it was paused
+	again := pausedEntry(t, st)
+	stopped, err = st.StopTimer(again.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stopped.EndedAt.Equal(*again.Segments[0].EndedAt) || stopped.Duration(time.Now()) != 20*time.Minute {
+		t.Errorf("stopping a paused timer = %+v", stopped)
+	}
+}
+
+func TestPauseUndoAndHistory(t *testing.T) {
+	st := newTestStore(t)
+	e, err := st.StartTimer(model.Entry{TS: ago(time.Hour), Text: "work"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.PauseTimer(e.ID); err != nil {
+		t.Fatal(err)
+	}
+	revs, err := st.History(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revs) != 1 || revs[0].Before.Paused() || !revs[0].After.Paused() {
+		t.Errorf("History after a pause = %+v", revs)
+	}
+
+	op, err := st.Undo()
+	if err != nil || op.Kind != "pause" {
+		t.Fatalf("Undo = %+v, %v", op, err)
+	}
+	got, err := st.GetEntry(e.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Paused() || len(got.Segments) != 0 {
+		t.Errorf("after undoing the pause = %+v", got)
+	}
+	if revs, _ := st.History(e.ID); len(revs) != 2 {
+		t.Errorf("undoing a pause should be a revision too, got %d", len(revs))
+	}
+}
+
+func TestSegmentsCascade(t *testing.T) {
+	st := newTestStore(t)
+	e := pausedEntry(t, st)
+	if err := st.DeleteEntries([]int64{e.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.PurgeEntries(nil); err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	if err := st.DB().QueryRow(`SELECT count(1) FROM segments`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("%d segments left after purging their entry", n)
+	}
+}
+
+func TestProjects(t *testing.T) {
+	st := newTestStore(t)
+	if _, err := st.SaveProject(model.Project{Name: "acme", Client: "ACME", Color: "#89B4FA"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SaveProject(model.Project{Name: "beta"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []string{"acme", "acme", "side", "beta"} {
+		mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "x", Project: p})
+	}
+
+	if _, err := st.RenameProject("acme", "beta"); !errors.Is(err, ErrProjectExists) {
+		t.Errorf("renaming onto a registered project = %v, want ErrProjectExists", err)
+	}
+	if _, err := st.RenameProject("nope", "other"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("renaming an unknown project = %v, want ErrProjectNotFound", err)
+	}
+	if n, err := st.RenameProject("acme", "acme-corp"); err != nil || n != 2 {
+		t.Fatalf("RenameProject = %d, %v", n, err)
+	}
+	if p, err := st.GetProject("acme-corp"); err != nil || p.Client != "ACME" || p.Color != "#89B4FA" {
+		t.Errorf("renamed project = %+v, %v", p, err)
+	}
+	if n, err := st.MergeProjects([]string{"side", "beta"}, "acme-corp"); err != nil || n != 2 {
+		t.Fatalf("MergeProjects = %d, %v", n, err)
+	}
+	if err := st.ArchiveProject("acme-corp", true); err != nil {
+		t.Fatal(err)
+	}
+
+	projects, err := st.Projects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 1 || projects[0].Name != "acme-corp" || !projects[0].Registered || projects[0].Entries != 4 || !projects[0].Archived() {
+		t.Errorf("Projects = %+v", projects)
+	}
+	if _, err := st.GetProject("beta"); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("merged project still registered: %v", err)
+	}
+
+	// Undo moves the entries back
+	if _, err := st.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := st.ListEntries(Filter{Project: "side"})
+	if err != nil || len(entries) != 1 {
+		t.Errorf("entries of side after undoing the merge = %d, %v", len(entries), err)
+	}
+}
+
+func TestProjectRenameMergeUndo(t *testing.T) {
+	st := newTestStore(t)
+	acme, err := st.SaveProject(model.Project{Name: "acme", Client: "ACME", Rate: 100, Budget: 10 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.SaveProject(model.Project{Name: "beta", Rate: 50}); err != nil {
+		t.Fatal(err)
+	}
+	e := mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "x", Project: "acme"})
+
+	if n, err := st.RenameProject("acme", "acme-corp"); err != nil || n != 1 {
+		t.Fatalf("RenameProject = %d, %v", n, err)
+	}
+	if op, err := st.Undo(); err != nil || !slices.Equal(op.Projects, []string{"acme"}) {
+		t.Fatalf("Undo rename = %+v, %v", op, err)
+	}
+	p, err := st.GetProject("acme")
+	if err != nil || p.ID != acme.ID || p.Client != "ACME" || p.Rate != 100 || p.Budget != 10*time.Hour {
+		t.Errorf("acme after undoing the rename = %+v, %v", p, err)
+	}
+	if got, _ := st.GetEntry(e.ID); got.Project != "acme" {
+		t.Errorf("entry project after undoing the rename = %q", got.Project)
+	}
+
+	// Without entries the rename is still journaled
+	if _, err := st.RenameProject("beta", "gamma"); err != nil {
+		t.Fatal(err)
+	}
+	if op, err := st.Undo(); err != nil || op.Kind != "rename" {
+		t.Fatalf("Undo of a rename without entries = %+v, %v", op, err)
+	}
+	if _, err := st.GetProject("beta"); err != nil {
+		t.Errorf("beta after undoing its rename: %v", err)
+	}
+
+	if _, err := st.MergeProjects([]string{"acme", "beta"}, "delta"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	projects, err := st.Projects()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, p := range projects {
+		names = append(names, p.Name)
+	}
+	if !slices.Equal(names, []string{"acme", "beta"}) {
+		t.Errorf("projects after undoing the merge = %v", names)
+	}
+}
+
+func TestRetag(t *testing.T) {
+	tests := []struct {
+		name  string
+		op    func(st *SQLite) (int, error)
+		n     int
+		after [3][]string // tags of the entries, the third one trashed
+		rates map[string]float64
+		gone  string // no longer found by search
+		found int    // live entries found by it again after undo
+	}{
+		{"rename", func(st *SQLite) (int, error) { return st.RenameTag("urgent", "asap") }, 2,
+			[3][]string{{"asap", "later"}, {"later"}, {"asap"}}, map[string]float64{"asap": 120}, "urgent", 1},
+		{"merge", func(st *SQLite) (int, error) { return st.MergeTags([]string{"urgent", "#later"}, "soon") }, 3,
+			[3][]string{{"soon"}, {"soon"}, {"soon"}}, map[string]float64{"soon": 120}, "later", 2},
+		{"merge into a tag in use", func(st *SQLite) (int, error) { return st.MergeTags([]string{"urgent"}, "later") }, 2,
+			[3][]string{{"later"}, {"later"}, {"later"}}, map[string]float64{"later": 120}, "urgent", 1},
+		{"delete", func(st *SQLite) (int, error) { return st.DeleteTags([]string{"later"}) }, 2,
+			[3][]string{{"urgent"}, nil, {"urgent"}}, map[string]float64{"urgent": 120}, "later", 2},
+		{"delete a rated tag", func(st *SQLite) (int, error) { return st.DeleteTags([]string{"urgent"}) }, 2,
+			[3][]string{{"later"}, {"later"}, nil}, map[string]float64{}, "urgent", 1},
+	}
+	initial := [3][]string{{"urgent", "later"}, {"later"}, {"urgent"}}
+	for _, tt := range tests {
+		st := newTestStore(t)
+		var entries [3]model.Entry
+		for i, tags := range initial {
+			entries[i] = mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "entry", Tags: tags})
+		}
+		if err := st.DeleteEntries([]int64{entries[2].ID}); err != nil {
+			t.Fatal(err)
+		}
+		if err := st.SetTagRate("urgent", 120); err != nil {
+			t.Fatal(err)
+		}
+		check := func(when string, want [3][]string, rates map[string]float64) {
+			t.Helper()
+			for i, e := range entries {
+				got, err := st.GetEntry(e.ID)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !slices.Equal(got.Tags, want[i]) {
+					t.Errorf("%s %s: entry %d tags %q, want %q", tt.name, when, i, got.Tags, want[i])
+				}
+			}
+			if got, err := st.TagRates(); err != nil || !maps.Equal(got, rates) {
+				t.Errorf("%s %s: rates %v, %v; want %v", tt.name, when, got, err, rates)
+			}
+		}
+
+		n, err := tt.op(st)
+		if err != nil || n != tt.n {
+			t.Fatalf("%s = %d, %v; want %d", tt.name, n, err, tt.n)
+		}
+		check("", tt.after, tt.rates)
+		if hits, err := st.Search(tt.gone, Filter{}); err != nil || len(hits) != 0 {
+			t.Errorf("%s: search %q found %d entries, %v", tt.name, tt.gone, len(hits), err)
+		}
+
+		if op, err := st.Undo(); err != nil || len(op.EntryIDs) != tt.n {
+			t.Fatalf("%s: Undo = %+v, %v", tt.name, op, err)
+		}
+		check("undone", initial, map[string]float64{"urgent": 120})
+		if hits, err := st.Search(tt.gone, Filter{}); err != nil || len(hits) != tt.found {
+			t.Errorf("%s undone: search %q = %+v, %v", tt.name, tt.gone, hits, err)
+		}
+		var names []string
+		rows, err := st.db.Query(`SELECT name FROM tags ORDER BY name`)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				t.Fatal(err)
+			}
+			names = append(names, name)
+		}
+		rows.Close()
+		if !slices.Equal(names, []string{"later", "urgent"}) {
+			t.Errorf("%s undone: tags %q, want later and urgent only", tt.name, names)
+		}
+	}
+
+	// A tag without entries has only its row, and undo still brings it back
+	st := newTestStore(t)
+	e := mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "entry", Tags: []string{"old"}})
+	if err := st.SetTagRate("old", 90); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.DeleteEntries([]int64{e.ID}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := st.PurgeEntries(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := st.DeleteTags([]string{"old"}); err != nil || n != 0 {
+		t.Fatalf("DeleteTags = %d, %v", n, err)
+	}
+	if op, err := st.Undo(); err != nil || !slices.Equal(op.Tags, []string{"old"}) {
+		t.Fatalf("Undo = %+v, %v", op, err)
+	}
+	if rates, err := st.TagRates(); err != nil || rates["old"] != 90 {
+		t.Errorf("rates after undo = %v, %v", rates, err)
+	}
+}
+
+func TestRetagErrors(t *testing.T) {
+	st := newTestStore(t)
+	mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "x", Tags: []string{"a", "b"}})
+	if _, err := st.RenameTag("a", "b"); !errors.Is(err, ErrTagExists) {
+		t.Errorf("renaming onto a tag in use = %v, want ErrTagExists", err)
+	}
+	if _, err := st.DeleteTags([]string{"a", "nope"}); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("deleting an unknown tag = %v, want ErrTagNotFound", err)
+	}
+	if _, err := st.MergeTags([]string{"a"}, "a"); err == nil {
+		t.Error("merging a tag into itself succeeded")
+	}
+}
+
+func TestInvoiceNumbers(t *testing.T) {
+	st := newTestStore(t)
+	acme, err := st.SaveProject(model.Project{Name: "acme", Rate: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	beta, err := st.SaveProject(model.Project{Name: "beta", Rate: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sept := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	inv := Invoice{ProjectID: acme.ID, Project: "acme", Start: sept, End: sept.AddDate(0, 1, 0), Total: 200, Currency: "EUR"}
+	first, err := st.IssueInvoice(inv, "INV-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Number != "INV-0001" || first.IssuedAt.IsZero() {
+		t.Errorf("first invoice = %+v", first)
+	}
+
+	// Re-issuing the same period keeps the number and updates the total
+	inv.Total = 300
+	again, err := st.IssueInvoice(inv, "INV-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Number != first.Number || again.ID != first.ID || again.Total != 300 {
+		t.Errorf("re-issued invoice = %+v, want number %s", again, first.Number)
+	}
+
+	// and so does renaming the project
+	if _, err := st.RenameProject("acme", "acme-corp"); err != nil {
+		t.Fatal(err)
+	}
+	inv.Project = "acme-corp"
+	renamed, err := st.IssueInvoice(inv, "INV-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Number != first.Number || renamed.Project != "acme-corp" {
+		t.Errorf("re-issuing after a rename = %+v, want number %s", renamed, first.Number)
+	}
+
+	tests := []struct {
+		name string
+		inv  Invoice
+		want string
+	}{
+		{"next month", Invoice{ProjectID: acme.ID, Project: "acme-corp", Start: inv.End, End: inv.End.AddDate(0, 1, 0)}, "INV-0002"},
+		{"other project", Invoice{ProjectID: beta.ID, Project: "beta", Start: inv.Start, End: inv.End}, "INV-0003"},
+	}
+	for _, tt := range tests {
+		got, err := st.IssueInvoice(tt.inv, "INV-")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Number != tt.want {
+			t.Errorf("%s: number %s, want %s", tt.name, got.Number, tt.want)
+		}
+	}
+}
+
+func TestTagRates(t *testing.T) {
+	st := newTestStore(t)
+	mustCreate(t, st, model.Entry{TS: ago(time.Hour), Category: "note", Text: "x", Tags: []string{"urgent", "review"}})
+	if err := st.SetTagRate("urgent", 180); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetTagRate("review", 120); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetTagRate("review", 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := st.SetTagRate("nope", 10); !errors.Is(err, ErrTagNotFound) {
+		t.Errorf("SetTagRate of an unknown tag = %v, want ErrTagNotFound", err)
+	}
+	rates, err := st.TagRates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 1 || rates["urgent"] != 180 {
+		t.Errorf("TagRates = %v", rates)
+	}
+}
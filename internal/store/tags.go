@@ -0,0 +1,203 @@
+package store
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ramanasai/pulse/internal/db"
+	"github.com/ramanasai/pulse/internal/model"
+)
+
+func (s *SQLite) Tags() ([]TagUsage, error) {
+	rows, err := s.db.Query(`
+		SELECT t.name, COUNT(*), MAX(e.ts), COALESCE(t.rate,0)
+		FROM tags t
+		JOIN entry_tags et ON et.tag_id = t.id
+		JOIN entries e ON e.id = et.entry_id AND e.deleted_at IS NULL
+		GROUP BY t.id
+		ORDER BY COUNT(*) DESC, t.name ASC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TagUsage
+	for rows.Next() {
+		var u TagUsage
+		var last string
+		if err := rows.Scan(&u.Name, &u.Entries, &last, &u.Rate); err != nil {
+			return nil, err
+		}
+		if u.LastUsed, err = db.ParseTime(last); err != nil {
+			return nil, fmt.Errorf("tag %s: %w", u.Name, err)
+		}
+		out = append(out, u)
+	}
+	return out, rows.Err()
+}
+
+func (s *SQLite) RenameTag(from, to string) (int, error) {
+	from, to = cleanTag(from), cleanTag(to)
+	if to == "" {
+		return 0, fmt.Errorf("new tag name is empty")
+	}
+	if from == to {
+		return 0, nil
+	}
+	return s.retag("rename", []string{from}, to)
+}
+
+func (s *SQLite) MergeTags(from []string, into string) (int, error) {
+	into = cleanTag(into)
+	if into == "" {
+		return 0, fmt.Errorf("target tag name is empty")
+	}
+	names := make([]string, len(from))
+	for i, f := range from {
+		if names[i] = cleanTag(f); names[i] == into {
+			return 0, fmt.Errorf("cannot merge #%s into itself", into)
+		}
+	}
+	return s.retag("merge", names, into)
+}
+
+func (s *SQLite) DeleteTags(names []string) (int, error) {
+	clean := make([]string, len(names))
+	for i, n := range names {
+		clean[i] = cleanTag(n)
+	}
+	return s.retag("untag", clean, "")
+}
+
+func cleanTag(s string) string {
+	return strings.TrimPrefix(strings.TrimSpace(s), "#")
+}
+
+// retag replaces every tag in from with to (or drops it when to is empty) on
+// the entries carrying it, then forgets the old tag names.
+func (s *SQLite) retag(kind string, from []string, to string) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	for _, f := range from {
+		var n int
+		if err := tx.QueryRow(`SELECT count(1) FROM tags WHERE name=?`, f).Scan(&n); err != nil {
+			return 0, err
+		}
+		if n == 0 {
+			return 0, fmt.Errorf("#%s: %w", f, ErrTagNotFound)
+		}
+	}
+	if kind == "rename" {
+		var n int
+		if err := tx.QueryRow(`SELECT count(1) FROM entry_tags et JOIN tags t ON t.id = et.tag_id WHERE t.name=?`, to).Scan(&n); err != nil {
+			return 0, err
+		}
+		if n > 0 {
+			return 0, fmt.Errorf("#%s: %w (use merge)", to, ErrTagExists)
+		}
+	}
+
+	cond, args := db.TagCondition("e.id", from, true)
+	rows, err := tx.Query(`SELECT `+entryColumns+` FROM entries e WHERE `+cond+` ORDER BY e.id`, args...)
+	if err != nil {
+		return 0, err
+	}
+	var before []model.Entry
+	for rows.Next() {
+		e, err := scanEntry(rows)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		before = append(before, e)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	opID, err := beginOp(tx, kind)
+	if err != nil {
+		return 0, err
+	}
+	if err := journalEntriesOf(tx, opID, before); err != nil {
+		return 0, err
+	}
+	// The rates live on the tags rows, which the entries don't carry
+	for _, t := range append(slices.Clone(from), to) {
+		if t == "" {
+			continue
+		}
+		if err := journalTag(tx, opID, t); err != nil {
+			return 0, err
+		}
+	}
+	for _, e := range before {
+		var tags []string
+		for _, t := range e.Tags {
+			if slices.Contains(from, t) {
+				t = to
+			}
+			if t != "" && !slices.Contains(tags, t) {
+				tags = append(tags, t)
+			}
+		}
+		e.Tags = tags
+		// writeEntry keeps entries.tags, and with it entries_fts, in step
+		if err := writeEntry(tx, e); err != nil {
+			return 0, err
+		}
+	}
+	in := strings.TrimSuffix(strings.Repeat("?,", len(from)), ",")
+	names := make([]any, len(from))
+	for i, f := range from {
+		names[i] = f
+	}
+	if to != "" {
+		// A renamed tag keeps its rate; a merge keeps the target's, if any
+		if _, err := tx.Exec(`UPDATE tags SET rate=(SELECT MAX(rate) FROM tags WHERE name IN (`+in+`))
+			WHERE name=? AND rate IS NULL`, append(names, to)...); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec(`DELETE FROM tags WHERE name IN (`+in+`)`, names...); err != nil {
+		return 0, err
+	}
+	return len(before), tx.Commit()
+}
+
+func (s *SQLite) SetTagRate(name string, rate float64) error {
+	name = cleanTag(name)
+	res, err := s.db.Exec(`UPDATE tags SET rate=NULLIF(?,0) WHERE name=?`, rate, name)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return fmt.Errorf("#%s: %w", name, ErrTagNotFound)
+	}
+	return nil
+}
+
+func (s *SQLite) TagRates() (map[string]float64, error) {
+	rows, err := s.db.Query(`SELECT name, rate FROM tags WHERE rate IS NOT NULL`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := map[string]float64{}
+	for rows.Next() {
+		var name string
+		var rate float64
+		if err := rows.Scan(&name, &rate); err != nil {
+			return nil, err
+		}
+		rates[name] = rate
+	}
+	return rates, rows.Err()
+}
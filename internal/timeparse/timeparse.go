@@ -0,0 +1,235 @@
+// Package timeparse turns the human-friendly times accepted on the command
+// line ("yesterday 15:00", "9:30", "2025-09-01") into time.Time values.
+// Everything is interpreted in the location of the reference time passed in,
+// which callers take from config.Location().
+package timeparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var clockLayouts = []string{"15:04", "15:04:05", "3pm", "3:04pm"}
+
+var dateLayouts = []string{"2006-01-02", "2006/01/02"}
+
+// Instant parses a point in time relative to now:
+//
+//	now | 14:30 | 3pm | today 9:00 | yesterday 15:00 | monday 10:00
+//	last friday | 2025-09-01 | 2025-09-01 14:30 | 2025-09-01T14:30:00Z
+//
+// A day without a clock means midnight; a clock without a day means today.
+func Instant(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("empty time")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(now.Location()), nil
+	}
+	if strings.EqualFold(s, "now") {
+		return now, nil
+	}
+
+	fields := strings.Fields(strings.ToLower(s))
+	// A trailing clock is optional; everything before it names the day.
+	clock, hasClock := parseClock(fields[len(fields)-1])
+	if hasClock {
+		fields = fields[:len(fields)-1]
+	}
+	day := midnight(now)
+	if len(fields) > 0 {
+		d, ok := parseDay(strings.Join(fields, " "), now)
+		if !ok {
+			return time.Time{}, fmt.Errorf("unrecognized time %q (try \"yesterday 15:00\", \"9:30\" or \"2025-09-01 14:00\")", s)
+		}
+		day = d
+	} else if !hasClock {
+		return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+	}
+	return At(day, clock), nil
+}
+
+// parseClock returns the offset of a wall clock time from midnight.
+func parseClock(s string) (time.Duration, bool) {
+	for _, layout := range clockLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+				time.Duration(t.Second())*time.Second, true
+		}
+	}
+	return 0, false
+}
+
+// parseDay resolves a day name to midnight of that day.
+func parseDay(s string, now time.Time) (time.Time, bool) {
+	today := midnight(now)
+	switch s {
+	case "today":
+		return today, true
+	case "yesterday":
+		return today.AddDate(0, 0, -1), true
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), true
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, now.Location()); err == nil {
+			return t, true
+		}
+	}
+	// "monday" is the most recent Monday (today if it is Monday); "last
+	// monday" always goes back at least a day.
+	name, last := strings.CutPrefix(s, "last ")
+	if wd, ok := weekday(name); ok {
+		back := (int(today.Weekday()) - int(wd) + 7) % 7
+		if last && back == 0 {
+			back = 7
+		}
+		return today.AddDate(0, 0, -back), true
+	}
+	// "3d ago"
+	if n, ok := strings.CutSuffix(s, "d ago"); ok {
+		if days, err := strconv.Atoi(strings.TrimSpace(n)); err == nil && days >= 0 {
+			return today.AddDate(0, 0, -days), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func weekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Range parses a period into its half-open bounds [start, end):
+//
+//	today | yesterday | this week | last week | this month | last month
+//	this year | last year | 7d | 2w | 12h
+//
+// Weeks start on Monday. "7d" is the last seven days including today and
+// "12h" the last twelve hours, both ending now. Anything Instant accepts is
+// also a range: a bare day covers that whole day, and a time with a clock is a
+// single point.
+//
+// Days begin dayStart after midnight (config day_starts_at), so with 04:00
+// "today" at 02:00 is still the previous day, running 04:00 to 04:00.
+func Range(s string, now time.Time, dayStart time.Duration) (time.Time, time.Time, error) {
+	// RFC 3339 is case-sensitive, so try it before normalising.
+	if t, err := time.Parse(time.RFC3339, strings.TrimSpace(s)); err == nil {
+		t = t.In(now.Location())
+		return t, t, nil
+	}
+	s = strings.ToLower(strings.Join(strings.Fields(s), " "))
+	if start, ok := parseOffset(s, now, dayStart); ok {
+		return start, now, nil
+	}
+	// Do the calendar arithmetic on the day now falls in, then move the
+	// boundaries to dayStart on their dates.
+	if start, end, ok := period(s, Day(now, dayStart)); ok {
+		return At(start, dayStart), At(end, dayStart), nil
+	}
+	t, err := Instant(s, now)
+	if err != nil {
+		return t, t, fmt.Errorf("unrecognized date or range %q (try today, yesterday, 7d, \"last monday\", \"this week\", \"last month\" or 2025-09-01)", s)
+	}
+	return t, t, nil
+}
+
+// period resolves named weeks, months, years and days to midnight bounds.
+func period(s string, now time.Time) (time.Time, time.Time, bool) {
+	today := midnight(now)
+	switch s {
+	case "this week", "last week":
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		if s == "last week" {
+			monday = monday.AddDate(0, 0, -7)
+		}
+		return monday, monday.AddDate(0, 0, 7), true
+	case "this month", "last month":
+		first := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+		if s == "last month" {
+			first = first.AddDate(0, -1, 0)
+		}
+		return first, first.AddDate(0, 1, 0), true
+	case "this year", "last year":
+		first := time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+		if s == "last year" {
+			first = first.AddDate(-1, 0, 0)
+		}
+		return first, first.AddDate(1, 0, 0), true
+	}
+	if day, ok := parseDay(s, now); ok {
+		return day, day.AddDate(0, 0, 1), true
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+// Since returns the start of the period s names.
+func Since(s string, now time.Time, dayStart time.Duration) (time.Time, error) {
+	start, _, err := Range(s, now, dayStart)
+	return start, err
+}
+
+// Until returns the end of the period s names, so "--until yesterday"
+// includes all of yesterday. Offsets count back from now: "--until 2h" stops
+// two hours ago.
+func Until(s string, now time.Time, dayStart time.Duration) (time.Time, error) {
+	start, end, err := Range(s, now, dayStart)
+	if _, ok := parseOffset(strings.ToLower(strings.TrimSpace(s)), now, dayStart); ok {
+		return start, err
+	}
+	return end, err
+}
+
+// Day returns the start of the day containing t, in t's location, for days
+// beginning dayStart after midnight.
+func Day(t time.Time, dayStart time.Duration) time.Time {
+	start := At(t, dayStart)
+	if t.Before(start) {
+		start = At(t.AddDate(0, 0, -1), dayStart)
+	}
+	return start
+}
+
+// At returns the wall clock time clock after midnight on t's date, in t's
+// location. Unlike adding clock to midnight it stays right on days when
+// daylight saving time begins or ends.
+func At(t time.Time, clock time.Duration) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, int(clock/time.Hour), int(clock%time.Hour/time.Minute),
+		int(clock%time.Minute/time.Second), 0, t.Location())
+}
+
+// parseOffset handles "7d", "2w" and "12h", returning the start of the period.
+// Day offsets start at dayStart.
+func parseOffset(s string, now time.Time, dayStart time.Duration) (time.Time, bool) {
+	if len(s) < 2 {
+		return time.Time{}, false
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n <= 0 {
+		return time.Time{}, false
+	}
+	today := Day(now, dayStart)
+	switch s[len(s)-1] {
+	case 'd':
+		return At(today.AddDate(0, 0, 1-n), dayStart), true
+	case 'w':
+		return At(today.AddDate(0, 0, 1-7*n), dayStart), true
+	case 'h':
+		return now.Add(-time.Duration(n) * time.Hour), true
+	}
+	return time.Time{}, false
+}
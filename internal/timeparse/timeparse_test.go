@@ -0,0 +1,137 @@
+package timeparse
+
+import (
+	"testing"
+	"time"
+)
+
+// now is Wednesday 2025-10-15 14:30 in UTC.
+var now = time.Date(2025, 10, 15, 14, 30, 0, 0, time.UTC)
+
+func date(y int, m time.Month, d, h, min int) time.Time {
+	return time.Date(y, m, d, h, min, 0, 0, time.UTC)
+}
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		in         string
+		start, end time.Time
+	}{
+		{"today", date(2025, 10, 15, 0, 0), date(2025, 10, 16, 0, 0)},
+		{"Yesterday", date(2025, 10, 14, 0, 0), date(2025, 10, 15, 0, 0)},
+		{"this week", date(2025, 10, 13, 0, 0), date(2025, 10, 20, 0, 0)},
+		{"last  week", date(2025, 10, 6, 0, 0), date(2025, 10, 13, 0, 0)},
+		{"this month", date(2025, 10, 1, 0, 0), date(2025, 11, 1, 0, 0)},
+		{"last month", date(2025, 9, 1, 0, 0), date(2025, 10, 1, 0, 0)},
+		{"last year", date(2024, 1, 1, 0, 0), date(2025, 1, 1, 0, 0)},
+		{"7d", date(2025, 10, 9, 0, 0), now},
+		{"2w", date(2025, 10, 2, 0, 0), now},
+		{"12h", date(2025, 10, 15, 2, 30), now},
+		{"monday", date(2025, 10, 13, 0, 0), date(2025, 10, 14, 0, 0)},
+		{"last wednesday", date(2025, 10, 8, 0, 0), date(2025, 10, 9, 0, 0)},
+		{"2025-09-01", date(2025, 9, 1, 0, 0), date(2025, 9, 2, 0, 0)},
+		{"2025-09-01 14:30", date(2025, 9, 1, 14, 30), date(2025, 9, 1, 14, 30)},
+		{"2025-09-01T14:30:00Z", date(2025, 9, 1, 14, 30), date(2025, 9, 1, 14, 30)},
+		{" 2025-09-01T16:30:00+02:00 ", date(2025, 9, 1, 14, 30), date(2025, 9, 1, 14, 30)},
+	}
+	for _, tt := range tests {
+		start, end, err := Range(tt.in, now, 0)
+		if err != nil {
+			t.Errorf("Range(%q): %v", tt.in, err)
+			continue
+		}
+		if !start.Equal(tt.start) || !end.Equal(tt.end) {
+			t.Errorf("Range(%q) = %v, %v; want %v, %v", tt.in, start, end, tt.start, tt.end)
+		}
+	}
+}
+
+func TestRangeErrors(t *testing.T) {
+	for _, in := range []string{"", "soon", "0d", "2025-13-01", "last fortnight"} {
+		if _, _, err := Range(in, now, 0); err == nil {
+			t.Errorf("Range(%q): want an error", in)
+		}
+	}
+}
+
+func TestSinceUntil(t *testing.T) {
+	tests := []struct {
+		in           string
+		since, until time.Time
+	}{
+		{"yesterday", date(2025, 10, 14, 0, 0), date(2025, 10, 15, 0, 0)},
+		{"2h", date(2025, 10, 15, 12, 30), date(2025, 10, 15, 12, 30)},
+		{"2025-09-01T14:30:00Z", date(2025, 9, 1, 14, 30), date(2025, 9, 1, 14, 30)},
+	}
+	for _, tt := range tests {
+		since, err := Since(tt.in, now, 0)
+		if err != nil || !since.Equal(tt.since) {
+			t.Errorf("Since(%q) = %v, %v; want %v", tt.in, since, err, tt.since)
+		}
+		until, err := Until(tt.in, now, 0)
+		if err != nil || !until.Equal(tt.until) {
+			t.Errorf("Until(%q) = %v, %v; want %v", tt.in, until, err, tt.until)
+		}
+	}
+}
+
+func TestDayStart(t *testing.T) {
+	early := date(2025, 10, 15, 2, 0)
+	start, end, err := Range("today", early, 4*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := date(2025, 10, 14, 4, 0); !start.Equal(want) || !end.Equal(want.AddDate(0, 0, 1)) {
+		t.Errorf("today at 02:00 with 04:00 day start = %v, %v", start, end)
+	}
+	if got := Day(early, 4*time.Hour); !got.Equal(date(2025, 10, 14, 4, 0)) {
+		t.Errorf("Day = %v", got)
+	}
+}
+
+func TestInstant(t *testing.T) {
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", now},
+		{"9:30", date(2025, 10, 15, 9, 30)},
+		{"3pm", date(2025, 10, 15, 15, 0)},
+		{"yesterday 15:00", date(2025, 10, 14, 15, 0)},
+		{"Monday 10:00", date(2025, 10, 13, 10, 0)},
+		{"2025-09-01T14:30:00Z", date(2025, 9, 1, 14, 30)},
+	}
+	for _, tt := range tests {
+		got, err := Instant(tt.in, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("Instant(%q) = %v, %v; want %v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDaylightSaving(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("no time zone database:", err)
+	}
+	// Clocks went forward at 02:00 on 2025-03-09 and back at 02:00 on 2025-11-02.
+	spring := time.Date(2025, 3, 9, 12, 0, 0, 0, ny)
+	got, err := Instant("today 9:00", spring)
+	if err != nil || got.Hour() != 9 {
+		t.Errorf("Instant(today 9:00) on a DST day = %v, %v", got, err)
+	}
+	start, end, err := Range("today", spring, 4*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start.Hour() != 4 || start.Day() != 9 || end.Hour() != 4 || end.Day() != 10 {
+		t.Errorf("today with 04:00 day start on a DST day = %v, %v", start, end)
+	}
+	fall := time.Date(2025, 11, 2, 10, 0, 0, 0, ny)
+	if day := Day(fall, 4*time.Hour); day.Hour() != 4 || day.Day() != 2 {
+		t.Errorf("Day on a DST day = %v", day)
+	}
+	if day := Day(time.Date(2025, 11, 3, 3, 30, 0, 0, ny), 4*time.Hour); day.Hour() != 4 || day.Day() != 2 {
+		t.Errorf("Day before the day start after a DST change = %v", day)
+	}
+}
@@ -2,23 +2,51 @@ package ui
 
 import (
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	pulse "github.com/ramanasai/pulse/internal/model"
 )
 
 type model struct {
 	vp      viewport.Model
-	entries []string
+	entries []pulse.Entry
 }
 
-func initialModel(entries []string) model {
+func initialModel(entries []pulse.Entry, loc *time.Location, colors map[string]string) model {
 	m := model{entries: entries}
+	lines := make([]string, 0, len(entries))
+	for _, e := range entries {
+		lines = append(lines, entryLine(e, loc, colors))
+	}
 	m.vp = viewport.New(0, 0)
-	m.vp.SetContent(strings.Join(entries, "\n\n"))
+	m.vp.SetContent(strings.Join(lines, "\n\n"))
 	return m
 }
 
+// entryLine renders "[15:04] project text" in the given timezone, with the
+// project in its color from colors (ProjectColor when it has none) and a
+// marker on running and paused timers.
+func entryLine(e pulse.Entry, loc *time.Location, colors map[string]string) string {
+	s := "[" + e.TS.In(loc).Format("15:04") + "] "
+	if e.Project != "" {
+		c, ok := colors[e.Project]
+		if !ok {
+			c = ProjectColor
+		}
+		s += lipgloss.NewStyle().Foreground(lipgloss.Color(c)).Render(e.Project) + " "
+	}
+	switch {
+	case e.Paused():
+		s += DefaultTheme.Warning.Render("⏸ paused") + " "
+	case e.Running():
+		s += DefaultTheme.Success.Render("● running") + " "
+	}
+	return s + e.Text
+}
+
 func (m model) Init() tea.Cmd { return nil }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -41,7 +69,9 @@ func (m model) View() string {
 	return header + "\n" + body + "\n" + hint
 }
 
-func Run(entries []string) error {
-	_, err := tea.NewProgram(initialModel(entries), tea.WithAltScreen()).Run()
+// Run shows entries until the user quits. colors maps project names to
+// lipgloss colors.
+func Run(entries []pulse.Entry, loc *time.Location, colors map[string]string) error {
+	_, err := tea.NewProgram(initialModel(entries, loc, colors), tea.WithAltScreen()).Run()
 	return err
 }
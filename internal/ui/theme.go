@@ -1,6 +1,11 @@
 package ui
 
-import "github.com/charmbracelet/lipgloss"
+import (
+	"math"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
 
 type Theme struct {
 	Title   lipgloss.Style
@@ -9,6 +14,7 @@ type Theme struct {
 	Border  lipgloss.Style
 	Hint    lipgloss.Style
 	Error   lipgloss.Style
+	Warning lipgloss.Style
 	Success lipgloss.Style
 }
 
@@ -19,5 +25,15 @@ var DefaultTheme = Theme{
 	Border:  lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(1),
 	Hint:    lipgloss.NewStyle().Faint(true).Foreground(lipgloss.Color("#CBA6F7")),
 	Error:   lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F38BA8")),
+	Warning: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#F9E2AF")),
 	Success: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#A6E3A1")),
 }
+
+// Bar renders percent (0-100, capped) as a width-cell progress bar.
+func Bar(percent float64, width int) string {
+	filled := int(math.Round(min(max(percent, 0), 100) / 100 * float64(width)))
+	return strings.Repeat("█", filled) + strings.Repeat("░", width-filled)
+}
+
+// ProjectColor is the color of projects that have none of their own.
+const ProjectColor = "#89B4FA"